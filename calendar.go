@@ -0,0 +1,141 @@
+package strtotime
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CalendarError is the error StrictCalendar makes Parse return for a date or time that doesn't exist,
+// such as "February 30", "2015-13-10" or "25:00".
+type CalendarError struct {
+	// Field is the part that is out of range: "month", "day", "hour", "minute" or "second"
+	Field string
+	Value int
+
+	// Year and Month are those the day was checked against. Year is 0 when the string didn't give
+	// one, in which case the day only has to exist in some year, as February 29th does, and Month is
+	// 0 for a day of the year, as in "2009.366".
+	Year  int
+	Month time.Month
+}
+
+func (e *CalendarError) Error() string {
+	switch {
+	case e.Field != "day" || e.Year == 0 && e.Month == 0:
+		return fmt.Sprintf("strtotime: Invalid %v %v", e.Field, e.Value)
+	case e.Month == 0:
+		return fmt.Sprintf("strtotime: %v has no day %v", e.Year, e.Value)
+	case e.Year != 0:
+		return fmt.Sprintf("strtotime: %v %v has no day %v", e.Month, e.Year, e.Value)
+	}
+
+	return fmt.Sprintf("strtotime: %v has no day %v", e.Month, e.Value)
+}
+
+// validate checks that the date and time r gives exist in the calendar. The hour can be 24 at the very end
+// of the day, and the second 60 when it is a leap second, which only ever comes at 23:59:60 UTC.
+func (r *result) validate() error {
+	if r.m != nil && (*r.m < 0 || *r.m > 11) {
+		return &CalendarError{Field: "month", Value: *r.m + 1}
+	}
+
+	if r.d != nil && r.firstOrLastDayOfMonth == 0 {
+		year := 0
+		month := time.January
+
+		if r.y != nil {
+			year = *r.y
+		}
+
+		if r.m != nil {
+			month = lookupNumberToMonth(*r.m)
+		}
+
+		switch {
+		// the day of the year, as in "2008.197"
+		case r.given&givenDate == givenDate && month == time.January && *r.d > 31:
+			if days := time.Date(year+1, time.January, 0, 0, 0, 0, 0, time.UTC).YearDay(); *r.d > days {
+				return &CalendarError{Field: "day", Value: *r.d, Year: year}
+			}
+		case r.m == nil && (*r.d < 1 || *r.d > 31):
+			return &CalendarError{Field: "day", Value: *r.d}
+		case r.m != nil && r.y == nil && (*r.d < 1 || *r.d > daysIn(2000, month)):
+			return &CalendarError{Field: "day", Value: *r.d, Month: month}
+		case r.m != nil && r.y != nil && (*r.d < 1 || *r.d > daysIn(year, month)):
+			return &CalendarError{Field: "day", Value: *r.d, Year: year, Month: month}
+		}
+	}
+
+	if r.h == nil {
+		return nil
+	}
+
+	h, i, s, f := *r.h, valueOr(r.i, 0), valueOr(r.s, 0), valueOr(r.f, 0)
+
+	switch {
+	case h < 0 || h > 24 || h == 24 && (i != 0 || s != 0 || f != 0):
+		return &CalendarError{Field: "hour", Value: h}
+	case i < 0 || i > 59:
+		return &CalendarError{Field: "minute", Value: i}
+	case s < 0 || s > 60 || s == 60 && ((h*60+i+valueOr(r.z, 0))%1440+1440)%1440 != 23*60+59:
+		return &CalendarError{Field: "second", Value: s}
+	}
+
+	return nil
+}
+
+// withOutOfRange adds formats reading numeric dates and times whose fields are out of range, such as "2015-13-45"
+// and "25:00", to the start of formats, so that StrictCalendar can report those fields rather than the
+// formats fail to read them, or read them as something else
+func withOutOfRange(formats []format) []format {
+	badMonth := group(digits(1, 2, func(d string) bool { return !between(0, 12)(d) }))
+	badDay := group(digits(1, 2, func(d string) bool { return !between(0, 31)(d) }))
+	badHour := group(digits(1, 2, func(d string) bool { return !between(0, 24)(d) }))
+	badMinute := group(digits(2, 2, func(d string) bool { return !between(0, 59)(d) }))
+	badSecond := group(digits(2, 2, func(d string) bool { return !between(0, 60)(d) }))
+	anyTwo := group(digits(2, 2, nil))
+
+	date := format{
+		rule: seq(year4, char("-"), alt(
+			seq(badMonth, char("-"), group(digits(1, 2, nil))),
+			seq(month, char("-"), badDay),
+		)),
+		name: "outofrangedate",
+		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
+			n := atoiAll(inputs)
+
+			return r.ymd(n[0], n[1]-1, n[2])
+		},
+	}
+
+	clock := format{
+		rule: seq(opt(word("t")), alt(
+			seq(badHour, char(":"), anyTwo, opt(seq(char(":"), anyTwo))),
+			seq(hour24, char(":"), badMinute, opt(seq(char(":"), anyTwo))),
+			seq(hour24, char(":"), anyTwo, char(":"), badSecond),
+		)),
+		name: "outofrangetime",
+		callback: func(r *result, inputs ...string) error {
+			r.given |= givenClock
+			n := atoiAll(inputs)
+
+			return r.time(n[0], n[1], n[2], 0)
+		},
+	}
+
+	return append([]format{date, clock}, formats...)
+}
+
+// atoiAll converts numbers of a few digits, reading those that are empty as 0
+func atoiAll(numbers []string) []int {
+	var n []int
+
+	for _, number := range numbers {
+		i, _ := strconv.Atoi(number)
+		n = append(n, i)
+	}
+
+	return n
+}
@@ -0,0 +1,107 @@
+package strtotime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var strictCalendarTests = []struct {
+	in  string
+	out time.Time
+	err *CalendarError
+}{
+	{"February 29", time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC), nil},
+	{"2016-02-29", time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC), nil},
+	{"2008.366", time.Date(2008, 12, 31, 0, 0, 0, 0, time.UTC), nil},
+	{"the 31st", time.Date(2015, 7, 31, 0, 0, 0, 0, time.UTC), nil},
+	{"24:00", time.Date(2015, 7, 6, 0, 0, 0, 0, time.UTC), nil},
+	{"2016-12-31 23:59:60", time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), nil},
+	{"2016-12-31 18:59:60 -05:00", time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), nil},
+
+	{"February 30", time.Time{}, &CalendarError{Field: "day", Value: 30, Month: time.February}},
+	{"2015-02-29", time.Time{}, &CalendarError{Field: "day", Value: 29, Year: 2015, Month: time.February}},
+	{"2015-04-31 10:00", time.Time{}, &CalendarError{Field: "day", Value: 31, Year: 2015, Month: time.April}},
+	{"January 00 2019", time.Time{}, &CalendarError{Field: "day", Value: 0, Year: 2019, Month: time.January}},
+	{"2009.366", time.Time{}, &CalendarError{Field: "day", Value: 366, Year: 2009}},
+	{"2015-00-10", time.Time{}, &CalendarError{Field: "month", Value: 0}},
+	{"24:00:01", time.Time{}, &CalendarError{Field: "hour", Value: 24}},
+	{"12:30:60", time.Time{}, &CalendarError{Field: "second", Value: 60}},
+	{"2015-13-45", time.Time{}, &CalendarError{Field: "month", Value: 13}},
+	{"2015-13-10", time.Time{}, &CalendarError{Field: "month", Value: 13}},
+	{"2015-02-45", time.Time{}, &CalendarError{Field: "day", Value: 45, Year: 2015, Month: time.February}},
+	{"2015-02-45 10:00", time.Time{}, &CalendarError{Field: "day", Value: 45, Year: 2015, Month: time.February}},
+	{"25:00", time.Time{}, &CalendarError{Field: "hour", Value: 25}},
+	{"t25:00:00", time.Time{}, &CalendarError{Field: "hour", Value: 25}},
+	{"12:75", time.Time{}, &CalendarError{Field: "minute", Value: 75}},
+	{"12:30:75", time.Time{}, &CalendarError{Field: "second", Value: 75}},
+}
+
+// unreadWithoutStrictCalendar are the strictCalendarTests that Parse fails to read without StrictCalendar,
+// rather than roll them over, as PHP does
+var unreadWithoutStrictCalendar = map[string]bool{
+	"2015-13-45": true,
+	"2015-13-10": true,
+	"25:00":      true,
+	"t25:00:00":  true,
+	"12:75":      true,
+	"12:30:75":   true,
+}
+
+func TestStrictCalendar(t *testing.T) {
+	for _, tt := range strictCalendarTests {
+		t.Run(tt.in, func(t *testing.T) {
+			u, err := Parse(tt.in, now.Unix(), StrictCalendar())
+
+			if tt.err == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if u != tt.out.Unix() {
+					t.Errorf("Result should have been %v, but it was %v", tt.out, time.Unix(u, 0).UTC())
+				}
+				return
+			}
+
+			if e, ok := err.(*CalendarError); !ok || !reflect.DeepEqual(e, tt.err) {
+				t.Errorf("Error should have been %v, but it was %v", tt.err, err)
+			}
+
+			if _, err := Parse(tt.in, now.Unix()); err != nil && !unreadWithoutStrictCalendar[tt.in] {
+				t.Errorf("%q should have been rolled over without StrictCalendar, but it failed with %v", tt.in, err)
+			}
+		})
+	}
+}
+
+func TestStrictCalendarElsewhere(t *testing.T) {
+	if _, err := Compile("February 30", StrictCalendar()); err == nil {
+		t.Error("Compile should have failed")
+	}
+
+	if all, err := ParseAll("February 30", now.Unix(), StrictCalendar()); err == nil {
+		t.Errorf("ParseAll should have failed, but it read %+v", all)
+	}
+
+	if _, _, err := ParsePrefix("2015-02-29 is not a date", now.Unix(), StrictCalendar()); err == nil {
+		t.Error("ParsePrefix should have failed")
+	}
+
+	if m := FindAll("Due 2015-02-29 or 2015-03-01", now.Unix(), StrictCalendar()); len(m) != 1 || m[0].Text != "2015-03-01" {
+		t.Errorf("FindAll should only have found 2015-03-01, but it found %+v", m)
+	}
+}
+
+func TestCalendarErrorMessages(t *testing.T) {
+	for err, want := range map[*CalendarError]string{
+		{Field: "hour", Value: 25}:                                  "strtotime: Invalid hour 25",
+		{Field: "day", Value: 0}:                                    "strtotime: Invalid day 0",
+		{Field: "day", Value: 30, Month: time.February}:             "strtotime: February has no day 30",
+		{Field: "day", Value: 29, Year: 2015, Month: time.February}: "strtotime: February 2015 has no day 29",
+		{Field: "day", Value: 366, Year: 2009}:                      "strtotime: 2009 has no day 366",
+	} {
+		if err.Error() != want {
+			t.Errorf("%+v should have read %q, but it read %q", *err, want, err.Error())
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package strtotime
+
+// Components tells which parts of a date an expression gave, such as the year and month of
+// "March 2024", as opposed to those filled in from the reference time or left at their start.
+type Components struct {
+	Year     bool
+	Month    bool
+	Day      bool
+	Weekday  bool
+	Hour     bool
+	Minute   bool
+	Second   bool
+	Fraction bool
+	Zone     bool
+
+	// Relative are the units the expression shifts by, from the finest, such as Daily and
+	// Monthly for "tomorrow +1 month". Weeks and fortnights are Weekly.
+	Relative []Frequency
+
+	// Granularity is the finest unit among the parts given and the shifts, such as Monthly
+	// for "March 2024" or Hourly for "tomorrow 3pm". Expressions that give no part, such as
+	// "now", are exact to the second.
+	Granularity Frequency
+}
+
+// Components returns the parts of a date the expression gave.
+func (e *Expr) Components() Components {
+	r := e.r
+	c := Components{
+		Year:        r.given&givenYear != 0,
+		Month:       r.given&givenMonth != 0,
+		Day:         r.given&givenDay != 0,
+		Weekday:     r.given&givenWeekday != 0,
+		Hour:        r.given&givenHour != 0,
+		Minute:      r.given&givenMinute != 0,
+		Second:      r.given&givenSecond != 0,
+		Fraction:    r.given&givenFraction != 0,
+		Zone:        r.given&givenZone != 0,
+		Granularity: Yearly,
+	}
+
+	for unit := Secondly; unit <= Yearly; unit++ {
+		if r.units&(1<<uint(unit)) != 0 {
+			c.Relative = append(c.Relative, unit)
+		}
+	}
+
+	// the finest unit of each part
+	finest := []struct {
+		given bool
+		unit  Frequency
+	}{
+		{c.Second || c.Fraction, Secondly},
+		{c.Minute, Minutely},
+		{c.Hour, Hourly},
+		{c.Day || c.Weekday, Daily},
+		{c.Month, Monthly},
+		{c.Year, Yearly},
+	}
+
+	found := len(c.Relative) > 0
+
+	if found {
+		c.Granularity = c.Relative[0]
+	}
+
+	for _, part := range finest {
+		if part.given && part.unit < c.Granularity {
+			c.Granularity = part.unit
+		}
+		found = found || part.given
+	}
+
+	if !found {
+		c.Granularity = Secondly
+	}
+
+	return c
+}
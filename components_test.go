@@ -0,0 +1,70 @@
+package strtotime
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var componentsTests = []struct {
+	in  string
+	out Components
+}{
+	{"now", Components{Granularity: Secondly}},
+	{"March 2024", Components{Year: true, Month: true, Granularity: Monthly}},
+	{"2024", Components{Hour: true, Minute: true, Granularity: Minutely}},
+	{"July 4th 2024", Components{Year: true, Month: true, Day: true, Granularity: Daily}},
+	{"July 4th", Components{Month: true, Day: true, Granularity: Daily}},
+	{"2008-08-07", Components{Year: true, Month: true, Day: true, Granularity: Daily}},
+	{"tomorrow 3pm", Components{Hour: true, Relative: []Frequency{Daily}, Granularity: Hourly}},
+	{"15:04:05.040", Components{Hour: true, Minute: true, Second: true, Fraction: true, Granularity: Secondly}},
+	{"friday 15:30 +05:00", Components{Weekday: true, Hour: true, Minute: true, Zone: true, Granularity: Minutely}},
+	{"+1 week 2 months", Components{Relative: []Frequency{Weekly, Monthly}, Granularity: Weekly}},
+	{"next year", Components{Relative: []Frequency{Yearly}, Granularity: Yearly}},
+	{"first day of next month", Components{Day: true, Relative: []Frequency{Monthly}, Granularity: Daily}},
+	{"@1569600000", Components{Year: true, Month: true, Day: true, Hour: true, Minute: true, Second: true, Granularity: Secondly}},
+}
+
+func TestComponents(t *testing.T) {
+	for _, test := range componentsTests {
+		e, err := Compile(test.in)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c := e.Components(); !reflect.DeepEqual(c, test.out) {
+			t.Errorf("The components of %q should have been %+v, but they were %+v", test.in, test.out, c)
+		}
+	}
+}
+
+// TestComponentsRoundTrip checks that the given parts survive the canonical string, and that the
+// structured form keeps all of the components
+func TestComponentsRoundTrip(t *testing.T) {
+	for _, e := range exprCorpus() {
+		c := e.Components()
+		back, err := Compile(e.String())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := back.Components(); got.Year != c.Year || got.Month != c.Month || got.Day != c.Day ||
+			got.Weekday != c.Weekday || got.Hour != c.Hour || got.Minute != c.Minute || got.Second != c.Second ||
+			got.Fraction != c.Fraction || got.Zone != c.Zone {
+			t.Errorf("%q gave %+v, but %+v once written", e, c, got)
+		}
+
+		data, _ := json.Marshal(e)
+		var j Expr
+
+		if err := json.Unmarshal(data, &j); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := j.Components(); !reflect.DeepEqual(got, c) {
+			t.Errorf("%s gave %+v, but %+v once read back", data, c, got)
+		}
+	}
+}
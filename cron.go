@@ -0,0 +1,567 @@
+package strtotime
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a standard 5-field cron expression: minute, hour, day of month, month and day of week.
+type Cron struct {
+	expr string
+
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// a day matches when both day fields do, unless neither is a wildcard,
+	// in which case matching either one is enough
+	domStar bool
+	dowStar bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCron takes a 5-field cron expression - such as "30 9 * * 1-5" - or one of the @yearly, @monthly, @weekly,
+// @daily, @midnight and @hourly macros. Fields may be lists, ranges and steps, and month and weekday names
+// are accepted. It returns the expression as a Cron, or an error if it is malformed.
+func ParseCron(expr string) (*Cron, error) {
+	expr = strings.TrimSpace(expr)
+	fields := strings.Fields(expr)
+
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		fields = strings.Fields(macro)
+	}
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf(`strtotime: A cron expression needs 5 fields: "%v"`, expr)
+	}
+
+	c := &Cron{
+		expr:    expr,
+		domStar: fields[2] == "*" || fields[2] == "?",
+		dowStar: fields[4] == "*" || fields[4] == "?",
+	}
+
+	var err error
+
+	if c.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+
+	if c.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+
+	if c.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, err
+	}
+
+	if c.month, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, err
+	}
+
+	if c.dow, err = parseCronField(fields[4], 0, 7, cronDays); err != nil {
+		return nil, err
+	}
+
+	// 7 is another name for sunday
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+
+	return c, nil
+}
+
+// parseCronField converts one field of a cron expression to a set of bits, one per value in [min, max].
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+
+	value := func(s string) (int, error) {
+		if n, ok := names[strings.ToLower(s)]; ok {
+			return n, nil
+		}
+
+		n, err := strconv.Atoi(s)
+
+		if err != nil || n < min || n > max {
+			return 0, fmt.Errorf(`strtotime: Invalid cron field: "%v"`, field)
+		}
+
+		return n, nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s < 1 {
+				return 0, fmt.Errorf(`strtotime: Invalid cron field: "%v"`, field)
+			}
+			step = s
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = value(bounds[1]); err != nil {
+				return 0, err
+			}
+		default:
+			var err error
+			if lo, err = value(part); err != nil {
+				return 0, err
+			}
+			// a single value with a step, such as "5/15", runs to the end of the range
+			if step == 1 {
+				hi = lo
+			}
+		}
+
+		if lo > hi {
+			return 0, fmt.Errorf(`strtotime: Invalid cron field: "%v"`, field)
+		}
+
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+
+	return bits, nil
+}
+
+// String returns the cron expression as it was given to ParseCron.
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Next returns the first time strictly after the given one that the cron expression fires at,
+// in the location of after, or the zero Time if it never fires, such as on February 30th.
+func (c *Cron) Next(after time.Time) time.Time {
+	t := nextMinute(after, after.Hour(), after.Minute()+1)
+
+	// every day of 8 years is enough to go through every leap year and weekday combination
+	limit := t.AddDate(8, 0, 0)
+
+	for t.Before(limit) {
+		y, m, d := t.Date()
+
+		switch {
+		case c.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = nextMinute(t, t.Hour()+1, 0)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = nextMinute(t, t.Hour(), t.Minute()+1)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// nextMinute returns the start of the given hour and minute of t's day, in t's location, which may
+// be offset from UTC by a fraction of an hour. When that isn't after t, as in the hour that repeats
+// when clocks go back, it returns the start of the minute after t.
+func nextMinute(t time.Time, hour, min int) time.Time {
+	y, m, d := t.Date()
+
+	if next := time.Date(y, m, d, hour, min, 0, 0, t.Location()); next.After(t) {
+		return next
+	}
+
+	return t.Truncate(time.Minute).Add(time.Minute)
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if !c.domStar && !c.dowStar {
+		return dom || dow
+	}
+
+	return dom && dow
+}
+
+// Describe returns an English description of the cron expression, such as "every weekday at 9:30am".
+func (c *Cron) Describe() string {
+	minutes, hours := bitsToList(c.minute, 0, 59), bitsToList(c.hour, 0, 23)
+
+	var clock string
+	subDaily := true
+
+	switch {
+	case len(minutes) == 60 && len(hours) == 24:
+		clock = "every minute"
+	case len(hours) == 24 && cronStep(minutes, 0, 59) > 1:
+		clock = fmt.Sprintf("every %v minutes", cronStep(minutes, 0, 59))
+	case len(hours) == 24 && len(minutes) == 1 && minutes[0] == 0:
+		clock = "every hour"
+	case len(hours) == 24 && len(minutes) == 1:
+		clock = fmt.Sprintf("every hour at minute %v", minutes[0])
+	case cronStep(hours, 0, 23) > 1 && len(minutes) == 1 && minutes[0] == 0:
+		clock = fmt.Sprintf("every %v hours", cronStep(hours, 0, 23))
+	case len(minutes)*len(hours) <= 4:
+		subDaily = false
+		var clocks []string
+		for _, h := range hours {
+			for _, i := range minutes {
+				clocks = append(clocks, formatClock(h, i))
+			}
+		}
+		clock = "at " + joinEnglish(clocks)
+	default:
+		clock = fmt.Sprintf("at minute %v past hour %v", joinEnglish(intsToStrings(minutes)), joinEnglish(intsToStrings(hours)))
+	}
+
+	var days []string
+
+	var months []string
+	for _, m := range bitsToList(c.month, 1, 12) {
+		months = append(months, time.Month(m).String())
+	}
+
+	if !c.domStar {
+		var ordinals []string
+		for _, d := range bitsToList(c.dom, 1, 31) {
+			ordinals = append(ordinals, ordinal(d))
+		}
+
+		of := "every month"
+		if len(months) < 12 {
+			of = joinEnglish(months)
+		}
+		days = append(days, "on the "+joinEnglish(ordinals)+" of "+of)
+	}
+
+	if !c.dowStar {
+		weekdays := bitsToList(c.dow, 0, 6)
+		switch fmt.Sprint(weekdays) {
+		case "[1 2 3 4 5]":
+			days = append(days, "every weekday")
+		case "[0 6]":
+			days = append(days, "every weekend")
+		default:
+			var names []string
+			for _, wd := range weekdays {
+				names = append(names, time.Weekday(wd).String())
+			}
+			days = append(days, "every "+joinEnglish(names))
+		}
+	}
+
+	day := strings.Join(days, " or ")
+
+	if day == "" && !subDaily {
+		day = "every day"
+	}
+
+	if len(months) < 12 && c.domStar {
+		day = strings.TrimSpace(day + " in " + joinEnglish(months))
+	}
+
+	switch {
+	case day == "":
+		return clock
+	case subDaily:
+		return clock + ", " + day
+	}
+
+	return day + " " + clock
+}
+
+// ToCron takes an English description of a repeating schedule - such as "every weekday at 9:30am" - and
+// returns it as a 5-field cron expression, such as "30 9 * * 1-5". Schedules without a time of day run at
+// midnight. It returns an error if the input cannot be recognized or cannot be expressed in cron.
+func ToCron(s string) (string, error) {
+	rec, err := ParseRecurrence(s, time.Time{})
+
+	if err != nil {
+		return "", err
+	}
+
+	return rec.Cron()
+}
+
+// Cron returns the recurrence as a 5-field cron expression, or an error if cron cannot express it,
+// as is the case for "every 3 weeks" or "every last friday of the month".
+func (rec *Recurrence) Cron() (string, error) {
+	unsupported := func(reason string) (string, error) {
+		return "", fmt.Errorf("strtotime: The recurrence cannot be expressed in cron: %v", reason)
+	}
+
+	if rec.Count > 0 || !rec.Until.IsZero() {
+		return unsupported("cron schedules never end")
+	}
+
+	if rec.Start.Second() != 0 || rec.Start.Nanosecond() != 0 || rec.Freq == Secondly {
+		return unsupported("cron has no seconds")
+	}
+
+	interval := rec.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	minute := []int{rec.Start.Minute()}
+	hour := []int{rec.Start.Hour()}
+	dom := []int{}
+	month := []int{}
+	dow := []int{}
+
+	for _, md := range rec.ByMonthDay {
+		if md < 0 {
+			return unsupported("cron cannot count days from the end of the month")
+		}
+		dom = append(dom, md)
+	}
+
+	for _, wd := range rec.ByDay {
+		if wd.N != 0 {
+			return unsupported("cron cannot pick the nth weekday of a month")
+		}
+		dow = append(dow, int(wd.Weekday))
+	}
+
+	for _, m := range rec.ByMonth {
+		month = append(month, int(m))
+	}
+
+	if len(dom) > 0 && len(dow) > 0 {
+		return unsupported("cron matches either the day of the month or the weekday, not both")
+	}
+
+	switch rec.Freq {
+	case Minutely:
+		if 60%interval != 0 {
+			return unsupported(fmt.Sprintf("minutes can only repeat on a divisor of 60, not every %v", interval))
+		}
+		minute = cronSeries(rec.Start.Minute(), interval, 0, 59)
+		hour = nil
+	case Hourly:
+		if 24%interval != 0 {
+			return unsupported(fmt.Sprintf("hours can only repeat on a divisor of 24, not every %v", interval))
+		}
+		hour = cronSeries(rec.Start.Hour(), interval, 0, 23)
+	case Daily:
+		if interval > 1 {
+			return unsupported(fmt.Sprintf("days can only repeat every day, not every %v", interval))
+		}
+	case Weekly:
+		if interval > 1 {
+			return unsupported(fmt.Sprintf("weeks can only repeat every week, not every %v", interval))
+		}
+		if len(dow) == 0 {
+			dow = []int{int(rec.Start.Weekday())}
+		}
+	case Monthly:
+		if 12%interval != 0 {
+			return unsupported(fmt.Sprintf("months can only repeat on a divisor of 12, not every %v", interval))
+		}
+		if interval > 1 {
+			if len(month) > 0 {
+				return unsupported("cron cannot combine a month interval with specific months")
+			}
+			month = cronSeries(int(rec.Start.Month()), interval, 1, 12)
+		}
+		if len(dom) == 0 && len(dow) == 0 {
+			dom = []int{rec.Start.Day()}
+		}
+	case Yearly:
+		if interval > 1 {
+			return unsupported(fmt.Sprintf("years can only repeat every year, not every %v", interval))
+		}
+		if len(dow) > 0 && len(month) == 0 {
+			return unsupported("cron cannot pick weekdays of a year")
+		}
+		if len(month) == 0 {
+			month = []int{int(rec.Start.Month())}
+		}
+		if len(dom) == 0 && len(dow) == 0 {
+			dom = []int{rec.Start.Day()}
+		}
+	}
+
+	return strings.Join([]string{
+		formatCronField(minute, 0, 59),
+		formatCronField(hour, 0, 23),
+		formatCronField(dom, 1, 31),
+		formatCronField(month, 1, 12),
+		formatCronField(dow, 0, 6),
+	}, " "), nil
+}
+
+// cronSeries returns every value in [min, max] that is a multiple of step away from start
+func cronSeries(start, step, min, max int) []int {
+	var values []int
+
+	for n := min + (start-min)%step; n <= max; n += step {
+		values = append(values, n)
+	}
+
+	return values
+}
+
+// formatCronField formats a set of values as a cron field, using wildcards, steps and ranges where it can.
+// No values at all, or every value in [min, max], is a wildcard.
+func formatCronField(values []int, min, max int) string {
+	sort.Ints(values)
+
+	if len(values) == 0 || len(values) == max-min+1 {
+		return "*"
+	}
+
+	if step := cronStep(values, min, max); step > 1 {
+		if values[0] == min {
+			return "*/" + strconv.Itoa(step)
+		}
+		return fmt.Sprintf("%v-%v/%v", values[0], max, step)
+	}
+
+	var parts []string
+
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+
+		switch {
+		case j-i >= 2:
+			parts = append(parts, fmt.Sprintf("%v-%v", values[i], values[j]))
+		case j-i == 1:
+			parts = append(parts, strconv.Itoa(values[i]), strconv.Itoa(values[j]))
+		default:
+			parts = append(parts, strconv.Itoa(values[i]))
+		}
+
+		i = j + 1
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// cronStep returns the step of values when there are at least 3 of them, evenly spaced and running
+// to the end of [min, max], as in "*/15" or "5-59/15". Otherwise it returns 0.
+func cronStep(values []int, min, max int) int {
+	if len(values) < 3 || values[0]-min >= values[1]-values[0] {
+		return 0
+	}
+
+	step := values[1] - values[0]
+
+	for i := 2; i < len(values); i++ {
+		if values[i]-values[i-1] != step {
+			return 0
+		}
+	}
+
+	if values[len(values)-1]+step <= max {
+		return 0
+	}
+
+	return step
+}
+
+func bitsToList(bits uint64, min, max int) []int {
+	var values []int
+
+	for n := min; n <= max; n++ {
+		if bits&(1<<uint(n)) != 0 {
+			values = append(values, n)
+		}
+	}
+
+	return values
+}
+
+func intsToStrings(values []int) []string {
+	s := make([]string, len(values))
+
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+
+	return s
+}
+
+// joinEnglish joins words as a list in an English sentence, such as "a, b and c"
+func joinEnglish(words []string) string {
+	if len(words) <= 1 {
+		return strings.Join(words, "")
+	}
+
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+// formatClock formats a time of day the way it is most often written in English, such as "9:30am" or "5pm"
+func formatClock(h, i int) string {
+	meridian := "am"
+
+	if h >= 12 {
+		meridian = "pm"
+	}
+
+	h = h % 12
+
+	if h == 0 {
+		h = 12
+	}
+
+	if i == 0 {
+		return fmt.Sprintf("%v%v", h, meridian)
+	}
+
+	return fmt.Sprintf("%v:%02d%v", h, i, meridian)
+}
+
+// ordinal formats a number as an English ordinal, such as "1st" or "12th"
+func ordinal(n int) string {
+	suffix := "th"
+
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,107 @@
+package strtotime
+
+import (
+	"testing"
+	"time"
+)
+
+var toCronTests = []struct {
+	in  string
+	out string
+}{
+	{"every weekday at 9:30am", "30 9 * * 1-5"},
+	{"every day at 9", "0 9 * * *"},
+	{"every 15 minutes", "*/15 * * * *"},
+	{"every 6 hours", "0 */6 * * *"},
+	{"every Monday and Friday at 5pm", "0 17 * * 1,5"},
+	{"on the 1st and 15th of every month", "0 0 1,15 * *"},
+	{"every weekend at noon", "0 12 * * 0,6"},
+	{"quarterly on the 1st", "0 0 1 */3 *"},
+}
+
+func TestToCron(t *testing.T) {
+	for _, tt := range toCronTests {
+		t.Run(tt.in, func(t *testing.T) {
+			expr, err := ToCron(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expr != tt.out {
+				t.Errorf("Expression should have been %v, but it was %v", tt.out, expr)
+			}
+		})
+	}
+}
+
+func TestToCronErrors(t *testing.T) {
+	for _, in := range []string{"every 3 weeks", "every other friday", "every last friday of the month", "every 7 minutes", "every 2 days"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ToCron(in); err == nil {
+				t.Errorf("%q should not have been expressible in cron", in)
+			}
+		})
+	}
+}
+
+var cronTests = []struct {
+	expr     string
+	next     []time.Time
+	describe string
+}{
+	{"30 9 * * 1-5", []time.Time{date(2015, 7, 6, 9, 30), date(2015, 7, 7, 9, 30)}, "every weekday at 9:30am"},
+	{"*/15 * * * *", []time.Time{date(2015, 7, 5, 13, 15), date(2015, 7, 5, 13, 30)}, "every 15 minutes"},
+	{"0 0 1,15 * *", []time.Time{date(2015, 7, 15, 0, 0), date(2015, 8, 1, 0, 0)}, "on the 1st and 15th of every month at 12am"},
+	{"0 12 * JAN,jul sun", []time.Time{date(2015, 7, 12, 12, 0), date(2015, 7, 19, 12, 0)}, "every Sunday in January and July at 12pm"},
+	{"0 0 13 * 5", []time.Time{date(2015, 7, 10, 0, 0), date(2015, 7, 13, 0, 0)}, "on the 13th of every month or every Friday at 12am"},
+	{"@hourly", []time.Time{date(2015, 7, 5, 14, 0), date(2015, 7, 5, 15, 0)}, "every hour"},
+	{"0 0 30 2 *", []time.Time{{}}, "on the 30th of February at 12am"},
+}
+
+func TestParseCron(t *testing.T) {
+	for _, tt := range cronTests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			after := now
+			for _, want := range tt.next {
+				after = c.Next(after)
+				if !after.Equal(want) {
+					t.Fatalf("Next fire time should have been %v, but it was %v", want, after)
+				}
+			}
+			if d := c.Describe(); d != tt.describe {
+				t.Errorf("Description should have been %q, but it was %q", tt.describe, d)
+			}
+		})
+	}
+}
+
+func TestCronNextInOffsetZones(t *testing.T) {
+	for _, loc := range []*time.Location{time.FixedZone("IST", 5*3600+30*60), time.FixedZone("NPT", 5*3600+45*60)} {
+		c, err := ParseCron("15 11 * * *")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		after := time.Date(2015, 7, 5, 10, 17, 0, 0, loc)
+
+		for _, want := range []time.Time{time.Date(2015, 7, 5, 11, 15, 0, 0, loc), time.Date(2015, 7, 6, 11, 15, 0, 0, loc)} {
+			after = c.Next(after)
+			if !after.Equal(want) {
+				t.Fatalf("Next fire time in %v should have been %v, but it was %v", loc, want, after)
+			}
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseCron(expr); err == nil {
+				t.Errorf("%q should not have been accepted", expr)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package strtotime
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period is an amount of time broken down into calendar and clock units, such as
+// "1 year 2 months 3 days 4 hours". Unlike a time.Duration, the length of its years, months
+// and days depends on the time it is added to.
+type Period struct {
+	Years       int
+	Months      int
+	Days        int
+	Hours       int
+	Minutes     int
+	Seconds     int
+	Nanoseconds int
+}
+
+// ParseDuration takes an English amount of time - such as "1 year 2 months 3 days 4h", "+90 minutes" or
+// "2 weeks ago" - or an ISO 8601 duration, such as "P1Y2M3DT4H", and returns it as a Period, or an error
+// if the input is not an amount of time. Weeks and fortnights are counted as days.
+func ParseDuration(s string) (Period, error) {
+	p, err := ParseISODuration(s)
+
+	if err == nil {
+		return p, nil
+	}
+
+	if _, ok := err.(*RangeError); ok {
+		return Period{}, err
+	}
+
+	r, err := parseFormats(s, durationFormats)
+
+	if err != nil {
+		return Period{}, err
+	}
+
+	// "next friday" is relative, but not an amount of time, and neither is "this month", nor nothing at all
+	if r.weekday != nil || r.units == 0 {
+		return Period{}, fmt.Errorf(`strtotime: "%v" is not a duration`, s)
+	}
+
+	return r.period(), nil
+}
+
+// durationFormats are the formats ParseDuration reads. Their relative shifts also take hours as "h" or "hr",
+// as in "4h", which Parse doesn't, since "15h" is a time of day in other languages.
+var durationFormats = func() []format {
+	var formats []format
+
+	for _, f := range allFormats {
+		switch f.name {
+		case "relative":
+			f.rule = seq(group(run("+-", 0)), run(" \t", 0), group(digits(1, anyLength, nil)), spaceOpt, group(word(concat(relTextUnit, []string{"week", "hr", "hrs", "h"})...)))
+			formats = append(formats, f)
+		case "relativetext", "ago", "whitespace":
+			formats = append(formats, f)
+		}
+	}
+
+	return formats
+}()
+
+// period returns the relative shifts of the result
+func (r *result) period() Period {
+	return Period{
+		Years:       r.ry,
+		Months:      r.rm,
+		Days:        r.rd,
+		Hours:       r.rh,
+		Minutes:     r.ri,
+		Seconds:     r.rs,
+		Nanoseconds: r.rf,
+	}
+}
+
+// AddTo returns t shifted by the period. Like Parse, it shifts the wall clock, letting
+// overflowing units carry over: January 31st plus 1 month is March 3rd, or 2nd in leap years.
+func (p Period) AddTo(t time.Time) time.Time {
+	y, m, d := t.Date()
+	hh, mm, ss := t.Clock()
+
+	return time.Date(y+p.Years, m+time.Month(p.Months), d+p.Days, hh+p.Hours, mm+p.Minutes, ss+p.Seconds, t.Nanosecond()+p.Nanoseconds, t.Location())
+}
+
+// Duration returns the period as a time.Duration, counting days as 24 hours.
+// It returns an error if the period has years or months, whose length varies.
+func (p Period) Duration() (time.Duration, error) {
+	if p.Years != 0 || p.Months != 0 {
+		return 0, fmt.Errorf("strtotime: A period with years or months has no fixed duration")
+	}
+
+	return time.Duration(p.Days)*24*time.Hour +
+		time.Duration(p.Hours)*time.Hour +
+		time.Duration(p.Minutes)*time.Minute +
+		time.Duration(p.Seconds)*time.Second +
+		time.Duration(p.Nanoseconds), nil
+}
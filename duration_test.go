@@ -0,0 +1,79 @@
+package strtotime
+
+import (
+	"testing"
+	"time"
+)
+
+var durationTests = []struct {
+	in  string
+	out Period
+}{
+	{"1 year 2 months 3 days 4h", Period{Years: 1, Months: 2, Days: 3, Hours: 4}},
+	{"3 days 4 hours", Period{Days: 3, Hours: 4}},
+	{"+90 minutes", Period{Minutes: 90}},
+	{"2 weeks ago", Period{Days: -14}},
+	{"next quarter", Period{Months: 3}},
+	{"-1 day +30 secs", Period{Days: -1, Seconds: 30}},
+	{"next month", Period{Months: 1}},
+	{"1 fortnight 2 hrs", Period{Days: 14, Hours: 2}},
+	{"1 hr 30 min", Period{Hours: 1, Minutes: 30}},
+	{"+500 ms", Period{Nanoseconds: 500000000}},
+	{"1 sec 250 milliseconds ago", Period{Seconds: -1, Nanoseconds: -250000000}},
+	{"3 microseconds 20 ns", Period{Nanoseconds: 3020}},
+	{"0 days", Period{}},
+}
+
+func TestParseDuration(t *testing.T) {
+	for _, tt := range durationTests {
+		t.Run(tt.in, func(t *testing.T) {
+			p, err := ParseDuration(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p != tt.out {
+				t.Errorf("Period should have been %+v, but it was %+v", tt.out, p)
+			}
+		})
+	}
+}
+
+func TestParseDurationErrors(t *testing.T) {
+	for _, in := range []string{"tomorrow", "next friday", "2015-07-05", "3pm", "soon", "", " ", "this month"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseDuration(in); err == nil {
+				t.Errorf("%q should not have been a duration", in)
+			}
+		})
+	}
+}
+
+func TestPeriodAddTo(t *testing.T) {
+	p := Period{Years: 1, Months: 2, Days: 3, Hours: 4}
+	if got, want := p.AddTo(now), time.Date(2016, 9, 8, 17, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Time should have been %v, but it was %v", want, got)
+	}
+
+	// shifting must agree with Parse
+	u, err := Parse("+1 year 2 months 3 days 4 hours", now.Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.AddTo(now).Unix(); got != u {
+		t.Errorf("Unix stamp should have been %v, but it was %v", u, got)
+	}
+}
+
+func TestPeriodDuration(t *testing.T) {
+	d, err := Period{Days: 1, Hours: 2, Minutes: 3, Seconds: 4, Nanoseconds: 5}.Duration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 26*time.Hour + 3*time.Minute + 4*time.Second + 5; d != want {
+		t.Errorf("Duration should have been %v, but it was %v", want, d)
+	}
+
+	if _, err := (Period{Months: 1}).Duration(); err == nil {
+		t.Error("A month should not have a fixed duration")
+	}
+}
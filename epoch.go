@@ -0,0 +1,118 @@
+package strtotime
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// epoch is a time counted from in units, as Unix timestamps count seconds from 1970
+type epoch struct {
+	origin time.Time
+	unit   time.Duration
+
+	// lotus is set for Excel's 1900 date system, which took from Lotus 1-2-3 a February 29th 1900 that
+	// didn't exist, so that its days before March start a day later than those after it
+	lotus bool
+}
+
+const wholeDay = 24 * time.Hour
+
+var unix = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// epochs are the epochs other than Unix time a count can be given in, such as "excel 45292"
+var epochs = map[string]epoch{
+	"excel":     {time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC), wholeDay, true},
+	"excel1900": {time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC), wholeDay, true},
+	"excel1904": {time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC), wholeDay, false},
+	// Julian days start at noon, on November 24th 4714 BC, in the Gregorian calendar
+	"jd":       {time.Date(-4713, time.November, 24, 12, 0, 0, 0, time.UTC), wholeDay, false},
+	"jdn":      {time.Date(-4713, time.November, 24, 12, 0, 0, 0, time.UTC), wholeDay, false},
+	"filetime": {time.Date(1601, time.January, 1, 0, 0, 0, 0, time.UTC), 100 * time.Nanosecond, false},
+	"ticks":    {time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC), 100 * time.Nanosecond, false},
+	"cocoa":    {time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC), time.Second, false},
+	"apple":    {time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC), time.Second, false},
+}
+
+// timestampUnits are the units "@" timestamps can be given in, such as "@1569600000123ms"
+var timestampUnits = map[string]time.Duration{
+	"":   time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"μs": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+// guessUnit returns the unit of a bare Unix timestamp going by how many digits it has: seconds up to 11, which
+// last until the year 5138, then milliseconds up to 14, microseconds up to 17, and nanoseconds
+func guessUnit(count string) time.Duration {
+	switch n := len(strings.TrimPrefix(count, "-")); {
+	case n <= 11:
+		return time.Second
+	case n <= 14:
+		return time.Millisecond
+	case n <= 17:
+		return time.Microsecond
+	}
+
+	return time.Nanosecond
+}
+
+// since sets r to the time count units after the origin of the epoch. The fraction is the digits after the
+// point, if count has any, and takes the sign of count.
+func (r *result) since(e epoch, count, fraction string) error {
+	r.given |= givenDate | givenClock
+	n, err := strconv.Atoi(count)
+
+	if err != nil {
+		return &RangeError{Value: count}
+	}
+
+	if e.lotus && n < 61 {
+		e.origin = e.origin.AddDate(0, 0, 1)
+	}
+
+	// the fraction of a unit, in nanoseconds
+	var sub int64
+
+	if fraction != "" {
+		f, _ := strconv.ParseFloat("0."+fraction, 64)
+		sub = int64(math.Round(f * float64(e.unit)))
+
+		if strings.HasPrefix(count, "-") {
+			sub = -sub
+		}
+	}
+
+	ok := true
+
+	switch {
+	case e.unit == wholeDay:
+		ok = addShift(&r.rd, n, 1)
+	case e.unit >= time.Second:
+		ok = addShift(&r.rs, n, int(e.unit/time.Second))
+	default:
+		perSecond := int(time.Second / e.unit)
+		ok = addShift(&r.rs, n/perSecond, 1)
+		sub += int64(n%perSecond) * int64(e.unit)
+	}
+
+	if !ok || !addShift(&r.rs, int(sub/int64(time.Second)), 1) {
+		return &RangeError{Value: count}
+	}
+
+	r.rf += int(sub % int64(time.Second))
+
+	y, m, d := e.origin.Date()
+	r.y = pointer(y)
+	r.m = pointer(int(m) - 1)
+	r.d = pointer(d)
+	r.dates = 0
+
+	r.resetTime()
+	r.h = pointer(e.origin.Hour())
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package strtotime
+
+import (
+	"testing"
+	"time"
+)
+
+var epochTests = []struct {
+	in  string
+	out time.Time
+}{
+	{"@1569600000.123", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"@-1.5", time.Date(1969, 12, 31, 23, 59, 58, 0, time.UTC)},
+	{"@1569600000123ms", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"@1569600000123456us", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"@1569600000123456µs", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"@1569600000123456789ns", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"@-1500ms", time.Date(1969, 12, 31, 23, 59, 58, 0, time.UTC)},
+	{"@1569600000123ms +1 day", time.Date(2019, 9, 28, 16, 0, 0, 0, time.UTC)},
+	{"excel 45292", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{"Excel 45292.75", time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)},
+	{"excel1900 45292", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{"excel 1", time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{"excel 59", time.Date(1900, 2, 28, 0, 0, 0, 0, time.UTC)},
+	{"excel 61", time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)},
+	{"excel1904 0", time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{"excel1904 43830", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{"jd 2440587.5", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{"JDN 2460311", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+	{"filetime 132136128000000000", time.Date(2019, 9, 22, 8, 0, 0, 0, time.UTC)},
+	{"ticks 637055712000000000", time.Date(2019, 10, 2, 0, 0, 0, 0, time.UTC)},
+	{"cocoa 591292800", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"cocoa -978307200", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{"apple 700000000", time.Date(2023, 3, 8, 20, 26, 40, 0, time.UTC)},
+}
+
+func TestEpochs(t *testing.T) {
+	for _, tt := range epochTests {
+		t.Run(tt.in, func(t *testing.T) {
+			u, err := Parse(tt.in, now.Unix())
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if u != tt.out.Unix() {
+				t.Errorf("Result should have been %v, but it was %v", tt.out, time.Unix(u, 0).UTC())
+			}
+		})
+	}
+}
+
+func TestEpochErrors(t *testing.T) {
+	for _, in := range []string{"@1569600000123msec", "excel 45292x", "excel1905 43830", "jd", "ticks 99999999999999999999"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := Parse(in, now.Unix()); err == nil {
+				t.Errorf("%q should have failed", in)
+			}
+		})
+	}
+}
+
+var bareTimestampTests = []struct {
+	in  string
+	out time.Time
+}{
+	{"1569600000", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"1569600000123", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"1569600000123456", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"1569600000123456789", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"1569600000.5", time.Date(2019, 9, 27, 16, 0, 0, 0, time.UTC)},
+	{"-1569600000123", time.Date(1920, 4, 6, 7, 59, 59, 0, time.UTC)},
+	{"1569600000123 +1 day", time.Date(2019, 9, 28, 16, 0, 0, 0, time.UTC)},
+	{"20150705", time.Date(2015, 7, 5, 0, 0, 0, 0, time.UTC)},
+	{"1569600000 seconds", time.Date(2065, 3, 31, 5, 0, 0, 0, time.UTC)},
+}
+
+func TestBareTimestamps(t *testing.T) {
+	for _, tt := range bareTimestampTests {
+		t.Run(tt.in, func(t *testing.T) {
+			u, err := Parse(tt.in, now.Unix(), BareTimestamps())
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if u != tt.out.Unix() {
+				t.Errorf("Result should have been %v, but it was %v", tt.out, time.Unix(u, 0).UTC())
+			}
+		})
+	}
+
+	if _, err := Parse("1569600000123", now.Unix()); err == nil {
+		t.Error("Bare timestamps should only be read with BareTimestamps")
+	}
+
+	if m := FindAll("sent 1569600000123, ack 1569600000456", now.Unix(), BareTimestamps()); len(m) != 2 {
+		t.Errorf("FindAll should have found both timestamps, but it found %+v", m)
+	}
+}
@@ -0,0 +1,488 @@
+package strtotime
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Expr is a compiled expression, such as "first day of next month 02:00", that can be evaluated
+// against any number of reference times without being parsed again. It is safe for concurrent use.
+type Expr struct {
+	r *result
+}
+
+// Compile parses s, with the same options as Parse, into an Expr.
+func Compile(s string, opts ...Option) (*Expr, error) {
+	r, err := parse(s, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expr{r: r}, nil
+}
+
+// Eval returns the time the expression refers to, relative to ref, as Parse would return it, or the
+// zero Time when it is too far from 1970 for a time.Time to hold. Unlike Parse, it keeps ref's
+// nanoseconds. Like Parse, it reads ref, and returns the time, in UTC, whatever ref's location: in
+// "tomorrow 9am", 9am is 9am UTC.
+func (e *Expr) Eval(ref time.Time) time.Time {
+	t, _ := e.r.clone().resolveAt(ref)
+	return t
+}
+
+// String returns the expression in its canonical form, which Compile reads back into the same
+// expression, so that expressions written differently but meaning the same, such as "tomorrow noon"
+// and "12pm +1 day", have the same string. Its parts come in this order, each only when needed:
+//
+//	first day of | last day of
+//	shifts, such as "+1 year +2 months -3 days +4 hours +5 minutes +6 seconds"
+//	a weekday, such as "friday" or "next friday", and "this week", "start of week" or "end of week"
+//	a time, as precise as the one given, such as "3pm", "15:04", "15:04:05" or "15:04:05.250",
+//	or "today" for midnight
+//	a time zone, such as "UTC" or "-03:00"
+//	a date, such as "2006-01-02", "January 2", "January", "next January" or a year
+//
+// An expression with none of them is "now". The day weeks start on, the preference and the overflow
+// aren't part of the string, and the expression must be compiled with the same WeekStart, Prefer and
+// MonthOverflow to mean the same. The parts of a date it gave, which Components returns, are kept, and
+// the string shifts by some unit whenever the expression does, so that Prefer leaves both alone, though
+// not always by the same units.
+func (e *Expr) String() string {
+	r := e.r.clone()
+	var parts []string
+
+	// days past the end of months, as in "2015.186", move to the shifts, since dates
+	// can't be written with them
+	if r.d != nil && *r.d > 31 {
+		r.rd += *r.d - 1
+		*r.d = 1
+	}
+
+	switch r.firstOrLastDayOfMonth {
+	case 1:
+		parts = append(parts, "first day of")
+	case -1:
+		parts = append(parts, "last day of")
+	}
+
+	shifts := []struct {
+		n    int
+		unit string
+	}{
+		{r.ry, "year"},
+		{r.rm, "month"},
+		{r.rd, "day"},
+		{r.rh, "hour"},
+		{r.ri, "minute"},
+		{r.rs, "second"},
+		{r.rf, "nanosecond"},
+	}
+
+	// nanoseconds are written in the largest unit that counts them whole
+	switch {
+	case r.rf%int(time.Millisecond) == 0:
+		shifts[6].n, shifts[6].unit = r.rf/int(time.Millisecond), "millisecond"
+	case r.rf%int(time.Microsecond) == 0:
+		shifts[6].n, shifts[6].unit = r.rf/int(time.Microsecond), "microsecond"
+	}
+
+	shifted := false
+
+	for _, shift := range shifts {
+		if shift.n != 0 {
+			parts = append(parts, fmt.Sprintf("%+d %vs", shift.n, shift.unit))
+			shifted = true
+		}
+	}
+
+	// an expression that shifts by units, even by nothing in all, isn't moved by Prefer, and must still shift
+	// when read back: "next friday" is written so, and shifts that cancel out, as "+1 day -1 day", as "+0 days",
+	// unless "today" is written
+	unshifted := r.units != 0 && !shifted && r.weekdayBehavior != 2 && !r.endOfWeek
+	noShift := len(parts)
+
+	if r.weekday != nil {
+		weekday := strings.ToLower(time.Weekday(*r.weekday).String())
+
+		if unshifted {
+			weekday = "next " + weekday
+		}
+
+		parts = append(parts, weekday)
+	}
+
+	startOfWeek := r.weekdayBehavior == 2 && r.weekday == nil && r.d == nil && r.given&givenDay != 0
+
+	switch {
+	case r.endOfWeek:
+		parts = append(parts, "end of week")
+	case startOfWeek:
+		parts = append(parts, "start of week")
+	case r.weekdayBehavior == 2:
+		parts = append(parts, "this week")
+	}
+
+	if r.hasTime() {
+		h, i, s, f := *r.h, valueOr(r.i, 0), valueOr(r.s, 0), valueOr(r.f, 0)
+		midnight := h == 0 && i == 0 && s == 0 && f == 0
+
+		switch {
+		case r.given&givenHour != 0:
+			parts = append(parts, formatTime(r.given, h, i, s, f))
+		// times that weekdays and weeks set themselves
+		case r.endOfWeek && h == 23 && i == 59 && s == 59 && f == 0:
+		case !r.endOfWeek && (r.weekday != nil || startOfWeek) && midnight:
+		case midnight:
+			parts = append(parts, "today")
+			unshifted = false
+		default:
+			parts = append(parts, formatTime(givenClock|givenFraction, h, i, s, f))
+		}
+	}
+
+	if unshifted && r.weekday == nil {
+		parts = append(parts[:noShift], append([]string{"+0 days"}, parts[noShift:]...)...)
+	}
+
+	if r.z != nil {
+		parts = append(parts, formatZone(*r.z))
+	}
+
+	parts = append(parts, formatDate(r)...)
+
+	if len(parts) == 0 {
+		return "now"
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// hasTime reports whether the result sets a time that a date won't reset to midnight
+func (r *result) hasTime() bool {
+	return r.h != nil && (r.dates == 0 || r.times > 0)
+}
+
+// valueOr returns what p points to, or def when it is nil
+func valueOr(p *int, def int) int {
+	if p == nil {
+		return def
+	}
+
+	return *p
+}
+
+// formatZone writes a zone correction, in minutes west of UTC, as tzCorrection reads it
+func formatZone(z int) string {
+	if z == 0 {
+		return "UTC"
+	}
+
+	sign := "+"
+
+	if z > 0 {
+		sign = "-"
+	} else {
+		z = -z
+	}
+
+	return fmt.Sprintf("%v%02d:%02d", sign, z/60, z%60)
+}
+
+// formatTime writes a time, as precise as the given components. Fractions of a second are written to
+// the millisecond, or to the micro or nanosecond when they need it.
+func formatTime(given component, h, i, s, f int) string {
+	switch {
+	case f%int(time.Microsecond) != 0:
+		return fmt.Sprintf("%02d:%02d:%02d.%09d", h, i, s, f)
+	case f%int(time.Millisecond) != 0:
+		return fmt.Sprintf("%02d:%02d:%02d.%06d", h, i, s, f/int(time.Microsecond))
+	case given&givenFraction != 0 || f != 0:
+		return fmt.Sprintf("%02d:%02d:%02d.%03d", h, i, s, f/int(time.Millisecond))
+	case given&givenSecond != 0 || s != 0:
+		return fmt.Sprintf("%02d:%02d:%02d", h, i, s)
+	case given&givenMinute != 0 || i != 0:
+		return fmt.Sprintf("%02d:%02d", h, i)
+	case h < 12:
+		return fmt.Sprintf("%dam", (h+11)%12+1)
+	}
+
+	return fmt.Sprintf("%dpm", (h+11)%12+1)
+}
+
+// formatDate writes the date of the result, as much of it as is set
+func formatDate(r *result) []string {
+	var parts []string
+
+	switch {
+	case r.y != nil && r.m != nil && r.d != nil && r.given&givenDay == 0 && *r.d == 1 && *r.y >= 1000 && *r.y <= 9999:
+		return []string{fmt.Sprintf("%v %04d", lookupNumberToMonth(*r.m), *r.y)}
+	case r.y != nil && r.m != nil && r.d != nil:
+		return []string{fmt.Sprintf("%04d-%02d-%02d", *r.y, *r.m+1, *r.d)}
+	case r.m != nil:
+		month := lookupNumberToMonth(*r.m).String()
+		switch r.relativeMonth {
+		case 1:
+			month = "next " + month
+		case -1:
+			month = "last " + month
+		}
+		parts = append(parts, month)
+		if r.d != nil {
+			parts[0] += fmt.Sprintf(" %d", *r.d)
+		}
+	case r.d != nil:
+		parts = append(parts, "the "+ordinal(*r.d))
+	}
+
+	if r.y != nil {
+		parts = append(parts, fmt.Sprintf("%04d", *r.y))
+	}
+
+	return parts
+}
+
+// exprVersion is the version of the structured form of expressions. Version 1, which is still read,
+// counted fractions of a second in milliseconds rather than nanoseconds.
+const exprVersion = 2
+
+// exprJSON is the structured form of an Expr. Months are numbered from 1, weekdays from 0 for
+// Sunday, and the UTC offset is in minutes east of UTC.
+type exprJSON struct {
+	Version int `json:"version"`
+
+	Year        *int `json:"year,omitempty"`
+	Month       *int `json:"month,omitempty"`
+	Day         *int `json:"day,omitempty"`
+	Hour        *int `json:"hour,omitempty"`
+	Minute      *int `json:"minute,omitempty"`
+	Second      *int `json:"second,omitempty"`
+	Nanosecond  *int `json:"nanosecond,omitempty"`
+	Millisecond *int `json:"millisecond,omitempty"`
+	UTCOffset   *int `json:"utcOffset,omitempty"`
+
+	Shift exprShiftJSON `json:"shift"`
+
+	Weekday       *int         `json:"weekday,omitempty"`
+	Week          string       `json:"week,omitempty"`
+	DayOfMonth    string       `json:"dayOfMonth,omitempty"`
+	RelativeMonth string       `json:"relativeMonth,omitempty"`
+	WeekStart     time.Weekday `json:"weekStart"`
+	Prefer        string       `json:"prefer,omitempty"`
+	Overflow      string       `json:"overflow,omitempty"`
+
+	Given []string `json:"given,omitempty"`
+	Units []string `json:"units,omitempty"`
+}
+
+// exprShiftJSON are the relative shifts of an Expr
+type exprShiftJSON struct {
+	Years        int `json:"years,omitempty"`
+	Months       int `json:"months,omitempty"`
+	Days         int `json:"days,omitempty"`
+	Hours        int `json:"hours,omitempty"`
+	Minutes      int `json:"minutes,omitempty"`
+	Seconds      int `json:"seconds,omitempty"`
+	Nanoseconds  int `json:"nanoseconds,omitempty"`
+	Milliseconds int `json:"milliseconds,omitempty"`
+}
+
+// componentNames are the names of the components in the structured form, in the order of their bits
+var componentNames = []string{"year", "month", "day", "weekday", "hour", "minute", "second", "fraction", "zone"}
+
+// names of the values of result fields in the structured form
+var (
+	weekNames          = map[string]int{"": 0, "this": 1, "end": 2}
+	dayOfMonthNames    = map[string]int{"": 0, "first": 1, "last": -1}
+	relativeMonthNames = map[string]int{"": 0, "next": 1, "last": -1}
+	preferNames        = map[string]int{"": 0, "future": int(PreferFuture), "past": int(PreferPast), "nearest": int(PreferNearest)}
+	overflowNames      = map[string]int{"": int(OverflowPHP), "clamp": int(OverflowClamp)}
+)
+
+// nameOf returns the name of value in names
+func nameOf(names map[string]int, value int) string {
+	for name, v := range names {
+		if v == value {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// indexOf returns the index of s in list, or -1
+func indexOf(list []string, s string) int {
+	for i, t := range list {
+		if t == s {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// MarshalJSON returns the structured form of the expression, which UnmarshalJSON reads back.
+func (e *Expr) MarshalJSON() ([]byte, error) {
+	r := e.r
+	j := exprJSON{
+		Version: exprVersion,
+		Year:    r.y,
+		Day:     r.d,
+		Shift: exprShiftJSON{
+			Years:       r.ry,
+			Months:      r.rm,
+			Days:        r.rd,
+			Hours:       r.rh,
+			Minutes:     r.ri,
+			Seconds:     r.rs,
+			Nanoseconds: r.rf,
+		},
+		Weekday:       r.weekday,
+		DayOfMonth:    nameOf(dayOfMonthNames, r.firstOrLastDayOfMonth),
+		RelativeMonth: nameOf(relativeMonthNames, r.relativeMonth),
+		WeekStart:     r.weekStart,
+		Prefer:        nameOf(preferNames, int(r.prefer)),
+		Overflow:      nameOf(overflowNames, int(r.overflow)),
+	}
+
+	if r.m != nil {
+		j.Month = pointer(*r.m + 1)
+	}
+
+	for bit, name := range componentNames {
+		if r.given&(1<<uint(bit)) != 0 {
+			j.Given = append(j.Given, name)
+		}
+	}
+
+	for unit, name := range unitNames {
+		if r.units&(1<<uint(unit)) != 0 {
+			j.Units = append(j.Units, name)
+		}
+	}
+
+	if r.hasTime() {
+		j.Hour = r.h
+		j.Minute = pointer(valueOr(r.i, 0))
+		j.Second = pointer(valueOr(r.s, 0))
+		j.Nanosecond = pointer(valueOr(r.f, 0))
+	}
+
+	if r.z != nil {
+		j.UTCOffset = pointer(-*r.z)
+	}
+
+	switch {
+	case r.endOfWeek:
+		j.Week = "end"
+	case r.weekdayBehavior == 2:
+		j.Week = "this"
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads the structured form of an expression that MarshalJSON returns.
+func (e *Expr) UnmarshalJSON(data []byte) error {
+	var j exprJSON
+
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	switch j.Version {
+	case 1:
+		if j.Millisecond != nil {
+			j.Nanosecond = pointer(*j.Millisecond * int(time.Millisecond))
+		}
+		j.Shift.Nanoseconds = j.Shift.Milliseconds * int(time.Millisecond)
+	case exprVersion:
+	default:
+		return fmt.Errorf("strtotime: Unsupported expression version %v", j.Version)
+	}
+
+	week, ok1 := weekNames[j.Week]
+	dayOfMonth, ok2 := dayOfMonthNames[j.DayOfMonth]
+	relativeMonth, ok3 := relativeMonthNames[j.RelativeMonth]
+	prefer, ok4 := preferNames[j.Prefer]
+	overflow, ok5 := overflowNames[j.Overflow]
+
+	switch {
+	case !ok1 || !ok2 || !ok3 || !ok4 || !ok5:
+		return fmt.Errorf("strtotime: Invalid expression %s", data)
+	case j.Month != nil && (*j.Month < 1 || *j.Month > 12):
+		return fmt.Errorf("strtotime: Invalid month %v", *j.Month)
+	case j.Weekday != nil && (*j.Weekday < 0 || *j.Weekday > 6):
+		return fmt.Errorf("strtotime: Invalid weekday %v", *j.Weekday)
+	}
+
+	r := &result{
+		y:                     j.Year,
+		d:                     j.Day,
+		h:                     j.Hour,
+		i:                     j.Minute,
+		s:                     j.Second,
+		f:                     j.Nanosecond,
+		ry:                    j.Shift.Years,
+		rm:                    j.Shift.Months,
+		rd:                    j.Shift.Days,
+		rh:                    j.Shift.Hours,
+		ri:                    j.Shift.Minutes,
+		rs:                    j.Shift.Seconds,
+		rf:                    j.Shift.Nanoseconds,
+		weekday:               j.Weekday,
+		weekStart:             j.WeekStart,
+		prefer:                Preference(prefer),
+		overflow:              Overflow(overflow),
+		endOfWeek:             week == 2,
+		firstOrLastDayOfMonth: dayOfMonth,
+		relativeMonth:         relativeMonth,
+	}
+
+	if j.Month != nil {
+		r.m = pointer(*j.Month - 1)
+		r.dates = 1
+	}
+
+	if j.Day != nil {
+		r.dates = 1
+	}
+
+	for _, name := range j.Given {
+		bit := indexOf(componentNames, name)
+		if bit < 0 {
+			return fmt.Errorf("strtotime: Invalid component %q", name)
+		}
+		r.given |= 1 << uint(bit)
+	}
+
+	for _, name := range j.Units {
+		unit := indexOf(unitNames, name)
+		if unit < 0 {
+			return fmt.Errorf("strtotime: Invalid unit %q", name)
+		}
+		r.shifted(Frequency(unit))
+	}
+
+	if j.Hour != nil {
+		r.times = 1
+	}
+
+	if j.UTCOffset != nil {
+		r.z = pointer(-*j.UTCOffset)
+		r.zones = 1
+	}
+
+	switch {
+	case week > 0:
+		r.weekdayBehavior = 2
+	case r.weekday != nil:
+		r.weekdayBehavior = 1
+	}
+
+	e.r = r
+
+	return nil
+}
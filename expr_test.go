@@ -0,0 +1,320 @@
+package strtotime
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var exprTests = []struct {
+	in  string
+	ref time.Time
+	out time.Time
+}{
+	{"first day of next month 02:00", now, time.Date(2015, 8, 1, 2, 0, 0, 0, time.UTC)},
+	{"first day of next month 02:00", time.Date(2015, 12, 20, 8, 0, 0, 0, time.UTC), time.Date(2016, 1, 1, 2, 0, 0, 0, time.UTC)},
+	{"next friday", now, time.Date(2015, 7, 10, 0, 0, 0, 0, time.UTC)},
+	{"next friday", time.Date(2015, 7, 13, 9, 0, 0, 0, time.UTC), time.Date(2015, 7, 17, 0, 0, 0, 0, time.UTC)},
+	{"+1 week 2 hours", now, time.Date(2015, 7, 12, 15, 0, 0, 0, time.UTC)},
+	{"+1 week 2 hours", time.Date(2015, 12, 30, 23, 0, 0, 0, time.UTC), time.Date(2016, 1, 7, 1, 0, 0, 0, time.UTC)},
+	{"+500 ms", now, time.Date(2015, 7, 5, 13, 0, 0, 500000000, time.UTC)},
+	{"250 milliseconds ago", now, time.Date(2015, 7, 5, 12, 59, 59, 750000000, time.UTC)},
+	{"10 usec", now, time.Date(2015, 7, 5, 13, 0, 0, 10000, time.UTC)},
+	{"+10 µs -3 ns", now, time.Date(2015, 7, 5, 13, 0, 0, 9997, time.UTC)},
+	{"next millisecond", now, time.Date(2015, 7, 5, 13, 0, 0, 1000000, time.UTC)},
+	{"01:59:59.04", now, time.Date(2015, 7, 5, 1, 59, 59, 40000000, time.UTC)},
+	{"2008-10-31T15:07:38.6875000-05:00", now, time.Date(2008, 10, 31, 20, 7, 38, 687500000, time.UTC)},
+	{"@1569600000.123", now, time.Date(2019, 9, 27, 16, 0, 0, 123000000, time.UTC)},
+	{"@-1ns", now, time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+	{"filetime 132136128000000001", now, time.Date(2019, 9, 22, 8, 0, 0, 100, time.UTC)},
+	{"+500 ms", time.Date(2015, 7, 5, 18, 0, 0, 700000000, time.UTC), time.Date(2015, 7, 5, 18, 0, 1, 200000000, time.UTC)},
+	{"now", time.Date(2015, 7, 5, 18, 0, 0, 7, time.UTC), time.Date(2015, 7, 5, 18, 0, 0, 7, time.UTC)},
+	{"tomorrow 9am", time.Date(2015, 7, 5, 22, 0, 0, 0, time.FixedZone("EDT", -4*60*60)), time.Date(2015, 7, 7, 9, 0, 0, 0, time.UTC)},
+}
+
+func TestExprEval(t *testing.T) {
+	for _, test := range exprTests {
+		e, err := Compile(test.in)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out := e.Eval(test.ref); !out.Equal(test.out) {
+			t.Errorf("%q evaluated against %v should have been %v, but it was %v", test.in, test.ref, test.out, out)
+		}
+	}
+}
+
+func TestExprEvalConcurrently(t *testing.T) {
+	e, err := Compile("last day of next month noon")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 48; i++ {
+		wg.Add(1)
+
+		go func(ref time.Time) {
+			defer wg.Done()
+
+			want, _ := Parse("last day of next month noon", ref.Unix())
+
+			for j := 0; j < 100; j++ {
+				if out := e.Eval(ref); out.Unix() != want {
+					t.Errorf("Evaluated against %v, the expression should have been %v, but it was %v", ref, time.Unix(want, 0).UTC(), out)
+					return
+				}
+			}
+		}(now.AddDate(0, i, 0))
+	}
+
+	wg.Wait()
+}
+
+func TestCompileErrors(t *testing.T) {
+	if _, err := Compile("nowhere"); err == nil {
+		t.Error("Compile should have failed")
+	}
+}
+
+var exprStringTests = []struct {
+	in  string
+	out string
+}{
+	{"now", "now"},
+	{"tomorrow noon", "+1 days 12pm"},
+	{"12pm +1 day", "+1 days 12pm"},
+	{"first day of next month 02:00", "first day of +1 months 02:00"},
+	{"next friday 3pm", "next friday 3pm"},
+	{"this friday", "next friday"},
+	{"friday", "friday"},
+	{"+1 day -1 day 3pm", "+0 days 3pm"},
+	{"today", "today"},
+	{"start of week", "start of week"},
+	{"last friday", "-7 days friday"},
+	{"monday next week", "+7 days monday this week"},
+	{"end of the week", "end of week"},
+	{"end of the week midnight", "end of week 12am"},
+	{"3 days ago 01:59:59.040pm +02:00", "-3 days 13:59:59.040 +02:00"},
+	{"July 4th", "July 4"},
+	{"next march", "next March"},
+	{"March 2024", "March 2024"},
+	{"March 1 2024", "2024-03-01"},
+	{"the 15th 3pm", "3pm the 15th"},
+	{"2008-08-07T18:11:31Z", "18:11:31 UTC 2008-08-07"},
+	{"@1569600000", "+1569600000 seconds 00:00:00 1970-01-01"},
+	{"@1569600000.123", "+1569600000 seconds +123 milliseconds 00:00:00 1970-01-01"},
+	{"+500 ms", "+500 milliseconds"},
+	{"250 milliseconds ago", "-250 milliseconds"},
+	{"+1500 usec", "+1500 microseconds"},
+	{"-1 ns", "-1 nanoseconds"},
+	{"13:59:59.04", "13:59:59.040"},
+	{"13:59:59.0400001", "13:59:59.040000100"},
+	{"13:59:59.123456", "13:59:59.123456"},
+}
+
+func TestExprString(t *testing.T) {
+	for _, test := range exprStringTests {
+		e, err := Compile(test.in)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if s := e.String(); s != test.out {
+			t.Errorf("%q should have been written %q, but it was %q", test.in, test.out, s)
+		}
+	}
+}
+
+// exprRefs are the reference times round trips are checked against
+var exprRefs = []time.Time{now, wednesday, time.Date(2016, 2, 29, 23, 59, 59, 0, time.UTC), time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC)}
+
+// exprCorpus returns the strings of the tests that compile, compiled with the options
+func exprCorpus(opts ...Option) []*Expr {
+	var in []string
+
+	for _, test := range parseTests {
+		in = append(in, test.in)
+	}
+
+	for _, test := range weekStartTests {
+		in = append(in, test.in)
+	}
+
+	for _, test := range exprStringTests {
+		in = append(in, test.in)
+	}
+
+	for _, test := range grammarTests {
+		in = append(in, test.in)
+	}
+
+	in = append(in, "front of 7pm", "2008-W28-3", "July 1999", "next July 2500", "1999", "last day of July", "end of week July", "saturday this week 9am")
+
+	var exprs []*Expr
+
+	for _, s := range in {
+		if e, err := Compile(s, opts...); err == nil {
+			exprs = append(exprs, e)
+		}
+	}
+
+	return exprs
+}
+
+func TestExprStringRoundTrip(t *testing.T) {
+	for _, e := range exprCorpus() {
+		s := e.String()
+		back, err := Compile(s)
+
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+
+		if back.String() != s {
+			t.Errorf("%q was written %q when read back", s, back.String())
+		}
+
+		for _, ref := range exprRefs {
+			if !back.Eval(ref).Equal(e.Eval(ref)) {
+				t.Errorf("%q evaluated against %v should have been %v, but it was %v", s, ref, e.Eval(ref), back.Eval(ref))
+			}
+		}
+	}
+}
+
+// TestExprStringRoundTripOptions checks that strings mean the same when compiled back with the options the
+// expressions were compiled with
+func TestExprStringRoundTripOptions(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"PreferFuture":  Prefer(PreferFuture),
+		"PreferPast":    Prefer(PreferPast),
+		"PreferNearest": Prefer(PreferNearest),
+		"WeekStart":     WeekStart(time.Monday),
+		"OverflowClamp": MonthOverflow(OverflowClamp),
+	} {
+		for _, e := range exprCorpus(opt) {
+			s := e.String()
+			back, err := Compile(s, opt)
+
+			if err != nil {
+				t.Errorf("%v %q: %v", name, s, err)
+				continue
+			}
+
+			for _, ref := range exprRefs {
+				if !back.Eval(ref).Equal(e.Eval(ref)) {
+					t.Errorf("%v %q evaluated against %v should have been %v, but it was %v", name, s, ref, e.Eval(ref), back.Eval(ref))
+				}
+			}
+		}
+	}
+}
+
+func TestExprJSONRoundTrip(t *testing.T) {
+	for _, e := range exprCorpus() {
+		data, err := json.Marshal(e)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var back Expr
+
+		if err := json.Unmarshal(data, &back); err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+
+		if again, _ := json.Marshal(&back); string(again) != string(data) {
+			t.Errorf("%s was marshaled as %s when read back", data, again)
+		}
+
+		for _, ref := range exprRefs {
+			if !back.Eval(ref).Equal(e.Eval(ref)) {
+				t.Errorf("%s evaluated against %v should have been %v, but it was %v", data, ref, e.Eval(ref), back.Eval(ref))
+			}
+		}
+	}
+}
+
+func TestExprJSON(t *testing.T) {
+	e, err := Compile("last day of +1 month 15:30 -05:00", WeekStart(time.Sunday))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(e)
+	want := `{"version":2,"hour":15,"minute":30,"second":0,"nanosecond":0,"utcOffset":-300,"shift":{"months":1},"dayOfMonth":"last","weekStart":0,"given":["day","hour","minute","zone"],"units":["month"]}`
+
+	if err != nil || string(data) != want {
+		t.Errorf("The expression should have been marshaled as %s, but it was %s (%v)", want, data, err)
+	}
+
+	for _, data := range []string{`{"version":3}`, `{"version":1,"month":13}`, `{"version":1,"week":"next"}`, `{"version":1,"weekday":7}`, `{"version":1,"given":["era"]}`, `{"version":1,"units":["lightyear"]}`, `{"version":1,"prefer":"soon"}`, `{"version":1,"overflow":"wrap"}`} {
+		if err := json.Unmarshal([]byte(data), &Expr{}); err == nil {
+			t.Errorf("%s should not have been accepted", data)
+		}
+	}
+}
+
+func TestExprJSONVersion1(t *testing.T) {
+	var e Expr
+
+	if err := json.Unmarshal([]byte(`{"version":1,"hour":15,"minute":30,"second":0,"millisecond":250,"shift":{"milliseconds":-500}}`), &e); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2015, 7, 5, 15, 29, 59, 750000000, time.UTC); !e.Eval(now).Equal(want) {
+		t.Errorf("The expression should have evaluated to %v, but it was %v", want, e.Eval(now))
+	}
+
+	if data, _ := json.Marshal(&e); !strings.Contains(string(data), `"nanosecond":250000000`) {
+		t.Errorf("The expression should have been marshaled in nanoseconds, but it was %s", data)
+	}
+}
+
+func TestExprJSONOptions(t *testing.T) {
+	e, err := Compile("January 31 +1 month", MonthOverflow(OverflowClamp))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(e)
+	var back Expr
+
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2015, 2, 28, 0, 0, 0, 0, time.UTC); !back.Eval(now).Equal(want) {
+		t.Errorf("%s should have evaluated to %v, but it was %v", data, want, back.Eval(now))
+	}
+
+	e, err = Compile("March 3", Prefer(PreferFuture))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ = json.Marshal(e)
+	back = Expr{}
+
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2016, 3, 3, 0, 0, 0, 0, time.UTC); !back.Eval(now).Equal(want) {
+		t.Errorf("%s should have evaluated to %v, but it was %v", data, want, back.Eval(now))
+	}
+}
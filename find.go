@@ -0,0 +1,248 @@
+package strtotime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match is a date or time found in text by FindAll.
+type Match struct {
+	// Start and End are the byte offsets of Text in the text searched
+	Start int
+	End   int
+	Text  string
+
+	// Time is the time Text refers to. Ranges, such as "9am to 5pm", run from Time to Until,
+	// which is the zero Time for anything else.
+	Time  time.Time
+	Until time.Time
+
+	// Formats are the names of the formats that read Text, in order
+	Formats []string
+}
+
+// rangeWords join two dates or times into a range
+var rangeWords = map[string]bool{
+	"to":      true,
+	"until":   true,
+	"till":    true,
+	"through": true,
+	"thru":    true,
+	"-":       true,
+	"–":       true,
+}
+
+// ambiguousWords are more often something else than a date when they stand alone
+var ambiguousWords = map[string]bool{
+	"may":    true,
+	"mar":    true,
+	"sun":    true,
+	"sat":    true,
+	"wed":    true,
+	"second": true,
+}
+
+// FindAll returns the dates and times in text, such as "March 3rd" and "next Tuesday at 2pm" in "Customer
+// called on March 3rd and wants a callback next Tuesday at 2pm", resolved relative to the unix timestamp as
+// Parse resolves them. Two of them joined by a word such as "to" or "until" make a range, whose end is resolved
+// relative to its start and must come after it. Words that are more often something else, such as "may" or "sun", are skipped when
+// they stand alone. The text is read in English, and nothing is found in it with InLocale set to another
+// language, as translating it would move the offsets of the matches.
+func FindAll(text string, relativeTo int64, opts ...Option) []Match {
+	o := newOptions(opts)
+
+	if !o.readsEnglish() {
+		return nil
+	}
+
+	in := lex(text)
+
+	var matches []Match
+
+	for pos := 0; pos < len(text); {
+		m, ok := findAt(in, pos, relativeTo, o)
+
+		if !ok {
+			t, _ := in.at(pos)
+			pos += len(t.text)
+			continue
+		}
+
+		if until, ok := findRangeEnd(in, m, o); ok {
+			m = Match{
+				Start:   m.Start,
+				End:     until.End,
+				Text:    text[m.Start:until.End],
+				Time:    m.Time,
+				Until:   until.Time,
+				Formats: append(m.Formats, until.Formats...),
+			}
+		}
+
+		matches = append(matches, m)
+		pos = m.End
+
+		for pos < len(text) && in.tokens[in.tokenAt[pos]].pos != pos {
+			pos++
+		}
+	}
+
+	return matches
+}
+
+// ParsePrefix parses the longest date or time at the start of s, such as "tomorrow 3pm" in "tomorrow 3pm buy
+// milk", relative to the unix timestamp as Parse does. It returns the time along with the rest of s, from the
+// first word after the date, or an error if s doesn't start with one. Like FindAll, it only reads English,
+// and fails with InLocale set to another language.
+func ParsePrefix(s string, relativeTo int64, opts ...Option) (time.Time, string, error) {
+	o := newOptions(opts)
+
+	if !o.readsEnglish() {
+		return time.Time{}, s, fmt.Errorf("strtotime: ParsePrefix only reads English, not %v", o.locale.Name)
+	}
+
+	in := lex(s)
+	start := skipSpace(s, 0)
+
+	steps, _ := matchFormats(in, start, o.formats())
+	r, n := longestDate(in, steps)
+
+	if n == 0 {
+		return time.Time{}, s, fmt.Errorf(`strtotime: Unrecognizable input: "%v"`, s)
+	}
+
+	if err := o.check(r); err != nil {
+		return time.Time{}, s, err
+	}
+
+	o.configure(r)
+
+	t, err := r.resolve(relativeTo)
+
+	if err != nil {
+		return time.Time{}, s, outOfRange(err, strings.TrimSpace(s[start:steps[n-1].end]))
+	}
+
+	return t, strings.TrimLeftFunc(s[steps[n-1].end:], unicode.IsSpace), nil
+}
+
+// findAt returns the longest date or time that starts at pos, a word of its own, if there is one
+func findAt(in *input, pos int, relativeTo int64, o *options) (Match, bool) {
+	if t, _ := in.at(pos); t == nil || t.kind == tokenSpace || !wordBoundary(in.s, pos) {
+		return Match{}, false
+	}
+
+	steps, _ := matchFormats(in, pos, o.formats())
+
+	if len(steps) > 0 && isFiller(steps[0]) {
+		return Match{}, false
+	}
+
+	r, n := longestDate(in, steps)
+
+	if n == 0 || o.check(r) != nil {
+		return Match{}, false
+	}
+
+	text := strings.TrimRightFunc(in.s[pos:steps[n-1].end], unicode.IsSpace)
+
+	if ambiguous(steps[:n], text) {
+		return Match{}, false
+	}
+
+	o.configure(r)
+	t, err := r.resolve(relativeTo)
+
+	if err != nil {
+		return Match{}, false
+	}
+
+	return Match{Start: pos, End: pos + len(text), Text: text, Time: t, Formats: stepNames(steps[:n])}, true
+}
+
+// longestDate returns how many of the steps, from the first, make the longest date, and its result. It leaves
+// out the formats at the end that aren't part of a date, that end within a word, or that contradict those
+// before them.
+func longestDate(in *input, steps []step) (*result, int) {
+	for n := len(steps); n > 0; n-- {
+		last := steps[n-1]
+
+		if isFiller(last) || !wordBoundary(in.s, last.end) {
+			continue
+		}
+
+		r := &result{}
+
+		if apply(r, steps[:n]) == nil {
+			return r, n
+		}
+	}
+
+	return nil, 0
+}
+
+// findRangeEnd returns the date or time that ends the range m starts, if a word such as "to" follows m and
+// what follows it comes after m
+func findRangeEnd(in *input, m Match, o *options) (Match, bool) {
+	pos := skipSpace(in.s, m.End)
+	t, start := in.at(pos)
+
+	if !start || !rangeWords[strings.ToLower(t.text)] {
+		return Match{}, false
+	}
+
+	end, ok := findAt(in, skipSpace(in.s, pos+len(t.text)), m.Time.Unix(), o)
+
+	if !ok || !end.Time.After(m.Time) {
+		return Match{}, false
+	}
+
+	return end, true
+}
+
+// readsEnglish reports whether the options leave text in English
+func (o *options) readsEnglish() bool {
+	return o.locale == nil || o.locale == English
+}
+
+// ambiguous reports whether the steps are a single format that is more often something else than a date,
+// such as "may", a number such as "1234", or a time zone. Numbers BareTimestamps reads as timestamps are
+// asked for, and so are not.
+func ambiguous(steps []step, text string) bool {
+	if len(steps) != 1 {
+		return false
+	}
+
+	switch steps[0].format.name {
+	case "tzcorrection", "utc":
+		return true
+	case "baretimestamp":
+		return false
+	}
+
+	return ambiguousWords[strings.ToLower(text)] || strings.Trim(text, "0123456789") == ""
+}
+
+// isFiller reports whether the step only joins dates and times, as commas and "at" do
+func isFiller(s step) bool {
+	return s.format.name == "whitespace" || s.format.name == "filler"
+}
+
+// wordBoundary reports whether no letter or digit comes both before and after byte i of s
+func wordBoundary(s string, i int) bool {
+	if i <= 0 || i >= len(s) {
+		return true
+	}
+
+	before, _ := utf8.DecodeLastRuneInString(s[:i])
+	after, _ := utf8.DecodeRuneInString(s[i:])
+
+	isWord := func(c rune) bool {
+		return unicode.IsLetter(c) || unicode.IsDigit(c)
+	}
+
+	return !isWord(before) || !isWord(after)
+}
@@ -0,0 +1,105 @@
+package strtotime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var findAllTests = []struct {
+	in  string
+	out []Match
+}{
+	{"Customer called on March 3rd and wants a callback next Tuesday at 2pm", []Match{
+		{Start: 19, End: 28, Text: "March 3rd", Time: time.Date(2015, 3, 3, 0, 0, 0, 0, time.UTC), Formats: []string{"datenoyear"}},
+		{Start: 50, End: 69, Text: "next Tuesday at 2pm", Time: time.Date(2015, 7, 7, 14, 0, 0, 0, time.UTC), Formats: []string{"relativetext", "filler", "timeTiny12"}},
+	}},
+	{"Shipped 2015-07-01, arriving in 3 days.", []Match{
+		{Start: 8, End: 18, Text: "2015-07-01", Time: time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC), Formats: []string{"gnudateshort | iso8601date2"}},
+		{Start: 32, End: 38, Text: "3 days", Time: time.Date(2015, 7, 8, 13, 0, 0, 0, time.UTC), Formats: []string{"relative"}},
+	}},
+	{"Out of office Jul 10 - Jul 12, 2015", []Match{
+		{Start: 14, End: 35, Text: "Jul 10 - Jul 12, 2015", Time: time.Date(2015, 7, 10, 0, 0, 0, 0, time.UTC), Until: time.Date(2015, 7, 12, 0, 0, 0, 0, time.UTC), Formats: []string{"datenoyear", "datetextual"}},
+	}},
+	{"Open tomorrow from 9am until 5pm", []Match{
+		{Start: 5, End: 13, Text: "tomorrow", Time: time.Date(2015, 7, 6, 13, 0, 0, 0, time.UTC), Formats: []string{"tomorrow"}},
+		{Start: 19, End: 32, Text: "9am until 5pm", Time: time.Date(2015, 7, 5, 9, 0, 0, 0, time.UTC), Until: time.Date(2015, 7, 5, 17, 0, 0, 0, time.UTC), Formats: []string{"timeTiny12", "timeTiny12"}},
+	}},
+	{"The deadline is 2015-08-01T12:00:00Z.", []Match{
+		{Start: 16, End: 36, Text: "2015-08-01T12:00:00Z", Time: time.Date(2015, 8, 1, 12, 0, 0, 0, time.UTC), Formats: []string{"wddx", "utc"}},
+	}},
+	{"Closed from 5pm to 9am", []Match{
+		{Start: 12, End: 15, Text: "5pm", Time: time.Date(2015, 7, 5, 17, 0, 0, 0, time.UTC), Formats: []string{"timeTiny12"}},
+		{Start: 19, End: 22, Text: "9am", Time: time.Date(2015, 7, 5, 9, 0, 0, 0, time.UTC), Formats: []string{"timeTiny12"}},
+	}},
+	{"I may be late, the sun is out", nil},
+	{"See ticket 12345 or call 555-1234 EST", nil},
+	{"nowhere, tomorrows", nil},
+	{"", nil},
+}
+
+func TestFindAll(t *testing.T) {
+	for _, test := range findAllTests {
+		t.Run(test.in, func(t *testing.T) {
+			out := FindAll(test.in, now.Unix())
+
+			if !reflect.DeepEqual(out, test.out) {
+				t.Errorf("Matches should have been %+v, but they were %+v", test.out, out)
+			}
+		})
+	}
+}
+
+func TestFindAllInLocale(t *testing.T) {
+	if out := FindAll("amanhã às 15:30", now.Unix(), InLocale(Portuguese)); out != nil {
+		t.Errorf("Matches should have been nil, but they were %+v", out)
+	}
+
+	if out := FindAll("tomorrow", now.Unix(), InLocale(English)); len(out) != 1 {
+		t.Errorf("Matches should have been tomorrow, but they were %+v", out)
+	}
+}
+
+var parsePrefixTests = []struct {
+	in   string
+	out  time.Time
+	rest string
+}{
+	{"tomorrow 3pm buy milk", time.Date(2015, 7, 6, 15, 0, 0, 0, time.UTC), "buy milk"},
+	{"next friday at noon: dentist", time.Date(2015, 7, 10, 12, 0, 0, 0, time.UTC), ": dentist"},
+	{"in 3 days call mom", time.Date(2015, 7, 8, 13, 0, 0, 0, time.UTC), "call mom"},
+	{"2015-08-01 at release", time.Date(2015, 8, 1, 0, 0, 0, 0, time.UTC), "at release"},
+	{"  noon", time.Date(2015, 7, 5, 12, 0, 0, 0, time.UTC), ""},
+	{"now nowhere", now, "nowhere"},
+}
+
+func TestParsePrefix(t *testing.T) {
+	for _, test := range parsePrefixTests {
+		t.Run(test.in, func(t *testing.T) {
+			out, rest, err := ParsePrefix(test.in, now.Unix())
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !out.Equal(test.out) {
+				t.Errorf("Result should have been %v, but it was %v", test.out, out)
+			}
+			if rest != test.rest {
+				t.Errorf("Rest should have been %q, but it was %q", test.rest, rest)
+			}
+		})
+	}
+}
+
+func TestParsePrefixErrors(t *testing.T) {
+	for _, in := range []string{"", "buy milk tomorrow", "at", "nowhere"} {
+		if _, rest, err := ParsePrefix(in, now.Unix()); err == nil || rest != in {
+			t.Errorf("ParsePrefix(%q) should have failed, leaving the string as it is", in)
+		}
+	}
+
+	in := "amanhã às 15:30 dentista"
+	if _, rest, err := ParsePrefix(in, now.Unix(), InLocale(Portuguese)); err == nil || rest != in {
+		t.Errorf("ParsePrefix(%q) should have failed in Portuguese, leaving the string as it is", in)
+	}
+}
@@ -8,53 +8,87 @@ import (
 	"time"
 )
 
-const (
-	reSpace    = "[ ]+"
-	reSpaceOpt = "[ ]*"
-	reMeridian = "(am|pm)"
-	reHour24   = "(2[0-4]|[01]?[0-9])"
-	reHour24lz = "([01][0-9]|2[0-4])"
-	reHour12   = "(0?[1-9]|1[0-2])"
-	reMinute   = "([0-5]?[0-9])"
-	reMinutelz = "([0-5][0-9])"
-	reSecond   = "(60|[0-5]?[0-9])"
-	reSecondlz = "(60|[0-5][0-9])"
-	reFrac     = "(?:\\.([0-9]+))"
-
-	reDayfull = "sunday|monday|tuesday|wednesday|thursday|friday|saturday"
-	reDayabbr = "sun|mon|tue|wed|thu|fri|sat"
-	reDaytext = reDayfull + "|" + reDayabbr + "|weekdays?"
-
-	reReltextnumber = "first|second|third|fourth|fifth|sixth|seventh|eighth?|ninth|tenth|eleventh|twelfth"
-	reReltexttext   = "next|last|previous|this"
-	reReltextunit   = "(?:second|sec|minute|min|hour|day|fortnight|forthnight|month|year)s?|weeks|" + reDaytext
-	reRelmvttext    = "(back|front)"
-
-	reYear          = "([0-9]{1,4})"
-	reYear2         = "([0-9]{2})"
-	reYear4         = "([0-9]{4})"
-	reYear4withSign = "([+-]?[0-9]{4})"
-	reMonth         = "(1[0-2]|0?[0-9])"
-	reMonthlz       = "(0[0-9]|1[0-2])"
-	reDay           = "(?:(3[01]|[0-2]?[0-9])(?:st|nd|rd|th)?)"
-	reDaylz         = "(0[0-9]|[1-2][0-9]|3[01])"
-
-	reMonthFull  = "january|february|march|april|may|june|july|august|september|october|november|december"
-	reMonthAbbr  = "jan|feb|mar|apr|may|jun|jul|aug|sept?|oct|nov|dec"
-	reMonthRoman = "i[vx]|vi{0,3}|xi{0,2}|i{1,3}"
-	reMonthText  = "(" + reMonthFull + "|" + reMonthAbbr + "|" + reMonthRoman + ")"
-
-	reTzCorrection = "((?:GMT)?([+-])" + reHour24 + ":?" + reMinute + "?)"
-	reDayOfYear    = "(00[1-9]|0[1-9][0-9]|[12][0-9][0-9]|3[0-5][0-9]|36[0-6])"
-	reWeekOfYear   = "(0[1-9]|[1-4][0-9]|5[0-3])"
-)
-
+// format is a way of writing part of a date, which its rule matches
 type format struct {
-	regex    string
+	rule     rule
 	name     string
 	callback func(r *result, inputs ...string) error
 }
 
+// relativeUnit is a unit relative shifts count in: the field of the result it shifts, how many of the field's
+// units it is worth, and its frequency
+type relativeUnit struct {
+	field func(r *result) *int
+	size  int
+	freq  Frequency
+}
+
+// relativeUnits are the units of relative shifts other than weekdays, by name. Parse doesn't read hours as
+// "hr", "hrs" or "h", but ParseDuration does.
+var relativeUnits = func() map[string]relativeUnit {
+	ry := func(r *result) *int { return &r.ry }
+	rm := func(r *result) *int { return &r.rm }
+	rd := func(r *result) *int { return &r.rd }
+	rh := func(r *result) *int { return &r.rh }
+	ri := func(r *result) *int { return &r.ri }
+	rs := func(r *result) *int { return &r.rs }
+	rf := func(r *result) *int { return &r.rf }
+
+	units := map[string]relativeUnit{}
+
+	for _, u := range []struct {
+		names []string
+		unit  relativeUnit
+	}{
+		{[]string{"ns", "nsec", "nsecs", "nanosecond", "nanoseconds"}, relativeUnit{rf, 1, Secondly}},
+		{[]string{"us", "µs", "μs", "usec", "usecs", "microsecond", "microseconds"}, relativeUnit{rf, int(time.Microsecond), Secondly}},
+		{[]string{"ms", "msec", "msecs", "millisecond", "milliseconds"}, relativeUnit{rf, int(time.Millisecond), Secondly}},
+		{[]string{"sec", "secs", "second", "seconds"}, relativeUnit{rs, 1, Secondly}},
+		{[]string{"min", "mins", "minute", "minutes"}, relativeUnit{ri, 1, Minutely}},
+		{[]string{"hour", "hours", "hr", "hrs", "h"}, relativeUnit{rh, 1, Hourly}},
+		{[]string{"day", "days"}, relativeUnit{rd, 1, Daily}},
+		{[]string{"week", "weeks"}, relativeUnit{rd, 7, Weekly}},
+		{[]string{"fortnight", "fortnights", "forthnight", "forthnights"}, relativeUnit{rd, 14, Weekly}},
+		{[]string{"month", "months"}, relativeUnit{rm, 1, Monthly}},
+		{[]string{"quarter", "quarters"}, relativeUnit{rm, 3, Monthly}},
+		{[]string{"year", "years"}, relativeUnit{ry, 1, Yearly}},
+	} {
+		for _, name := range u.names {
+			units[name] = u.unit
+		}
+	}
+
+	return units
+}()
+
+// shiftBy shifts r by amount of the unit, which may be a weekday: "+2 fridays" is the second friday from now,
+// and "-1 friday" the last one. It returns false when the shift overflows.
+func (r *result) shiftBy(amount int, unit string) bool {
+	unit = strings.ToLower(unit)
+
+	if u, ok := relativeUnits[unit]; ok {
+		return addShift(u.field(r), amount, u.size)
+	}
+
+	switch unit {
+	case "mon", "monday", "tue", "tuesday", "wed", "wednesday", "thu", "thursday", "fri", "friday", "sat", "saturday", "sun", "sunday":
+		r.resetDayTime()
+		r.weekday = pointer(lookupWeekday(unit, 7))
+		r.given |= givenWeekday
+		r.weekdayBehavior = 1
+		r.shifted(Weekly)
+
+		weeks := amount
+		if amount > 0 {
+			weeks = amount - 1
+		}
+		return addShift(&r.rd, weeks, 7)
+	}
+
+	// TODO: implement "weekday" and "weekdays"
+	return true
+}
+
 func pointer(x int) *int {
 	return &x
 }
@@ -62,10 +96,11 @@ func pointer(x int) *int {
 func formats() []format {
 
 	yesterday := format{
-		regex: `(yesterday)`,
-		name:  "yesterday",
+		rule: group(word("yesterday")),
+		name: "yesterday",
 		callback: func(r *result, inputs ...string) error {
 			r.rd--
+			r.shifted(Daily)
 			//HACK: Original code had call to r.resetTime()
 			// Might have to do with timezone adjustment
 			return nil
@@ -73,65 +108,70 @@ func formats() []format {
 	}
 
 	now := format{
-		regex: `(now)`,
-		name:  "now",
+		rule: group(word("now")),
+		name: "now",
 		callback: func(r *result, inputs ...string) error {
 			return nil
 		},
 	}
 
 	noon := format{
-		regex: `(noon)`,
-		name:  "noon",
+		rule: group(word("noon")),
+		name: "noon",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenHour
 			r.resetTime()
 			return r.time(12, 0, 0, 0)
 		},
 	}
 
 	midnightOrToday := format{
-		regex: `(midnight|today)`,
-		name:  "midnight | today",
+		rule: group(word("midnight", "today")),
+		name: "midnight | today",
 		callback: func(r *result, inputs ...string) error {
+			if strings.ToLower(inputs[0]) == "midnight" {
+				r.given |= givenHour
+			} else {
+				r.shifted(Daily)
+			}
 			return r.resetTime()
 		},
 	}
 
 	tomorrow := format{
-		regex: "(tomorrow)",
-		name:  "tomorrow",
+		rule: group(word("tomorrow")),
+		name: "tomorrow",
 		callback: func(r *result, inputs ...string) error {
 			r.rd++
+			r.shifted(Daily)
 			// Original code calls r.resetTime() here.
 			return nil
 		},
 	}
 
 	timestamp := format{
-		regex: `^@(-?\d+)`,
-		name:  "timestamp",
+		rule: seq(char("@"), group(seq(opt(char("-")), digits(1, anyLength, nil))), opt(frac), opt(group(word("ms", "us", "µs", "μs", "ns")))),
+		name: "timestamp",
 		callback: func(r *result, inputs ...string) error {
-			s, err := strconv.Atoi(inputs[0])
-
-			if err != nil {
-				return err
-			}
-
-			r.rs += s
-			r.y = pointer(1970)
-			r.m = pointer(0)
-			r.d = pointer(1)
-			r.dates = 0
-
-			return r.resetTime()
+			return r.since(epoch{origin: unix, unit: timestampUnits[strings.ToLower(inputs[2])]}, inputs[0], inputs[1])
 			// original code called r.zone(0)
 		},
 	}
 
+	namedEpoch := format{
+		rule: seq(group(alt(seq(word("excel"), digits(4, 4, func(d string) bool { return d == "1900" || d == "1904" })),
+			word("excel", "jd", "jdn", "filetime", "ticks", "cocoa", "apple"))), space, group(seq(opt(char("-")), digits(1, anyLength, nil))), opt(frac)),
+		name: "namedepoch",
+		callback: func(r *result, inputs ...string) error {
+			return r.since(epochs[strings.ToLower(inputs[0])], inputs[1], inputs[2])
+		},
+	}
+
 	firstOrLastDay := format{
-		regex: `^(first|last) day of`,
-		name:  "firstdayof | lastdayof",
+		rule: seq(group(word("first", "last")), char(" "), word("day"), char(" "), word("of")),
+		name: "firstdayof | lastdayof",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDay
 			if strings.ToLower(inputs[0]) == "first" {
 				r.firstOrLastDayOfMonth = 1
 				return nil
@@ -142,7 +182,7 @@ func formats() []format {
 	}
 
 	// weekdayOf := format{
-	// 	regex: "(?i)^(" + reReltextnumber + "|" + reReltexttext + ") (" + reDayfull + "|" + reDayabbr + ")" + " of",
+	// 	rule:  seq(group(word(concat(relTextNumber, relTextText)...)), char(" "), group(word(concat(dayFull, dayAbbr)...)), char(" "), word("of")),
 	// 	name:  "weekdayof",
 	// 	callback: func(r *result, inputs ...string) error {
 	// 		relValue := inputs[0]
@@ -155,9 +195,10 @@ func formats() []format {
 	// }
 
 	backOrFrontOf := format{
-		regex: "(?i)^(" + reMonthFull + ") " + reDaylz + " " + reYear + " " + reRelmvttext + " of " + reHour24 + reMeridian,
-		name:  "backof | frontof",
+		rule: seq(group(word(monthFull...)), char(" "), daylz, char(" "), year, char(" "), group(word("back", "front")), char(" "), word("of"), char(" "), hour24, meridian),
+		name: "backof | frontof",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate | givenHour | givenMinute
 			year, err := strconv.Atoi(inputs[2])
 			if err != nil {
 				return nil
@@ -181,9 +222,10 @@ func formats() []format {
 	}
 
 	mssqltime := format{
-		regex: "^" + reHour24 + ":" + reMinutelz + ":" + reSecondlz + "[:.]([0-9]+)" + reMeridian + "?",
-		name:  "mssqltime",
+		rule: seq(hour24, char(":"), minutelz, char(":"), secondlz, char(":."), group(digits(1, anyLength, nil)), opt(meridian)),
+		name: "mssqltime",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenClock | givenFraction
 
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -200,7 +242,7 @@ func formats() []format {
 				return err
 			}
 
-			frac, err := strconv.Atoi(inputs[3][0:3])
+			frac, err := processFraction(inputs[3])
 			if err != nil {
 				return err
 			}
@@ -215,9 +257,10 @@ func formats() []format {
 	}
 
 	timeLong12 := format{
-		regex: "^" + reHour12 + "[:.]" + reMinute + "[:.]" + reSecondlz + reSpaceOpt + reMeridian,
-		name:  "timeLong12",
+		rule: seq(hour12, char(":."), minute, char(":."), secondlz, spaceOpt, meridian),
+		name: "timeLong12",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenClock
 
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -241,9 +284,10 @@ func formats() []format {
 	}
 
 	timeShort12 := format{
-		regex: "^" + reHour12 + "[:.]" + reMinutelz + reSpaceOpt + reMeridian,
-		name:  "timeShort12",
+		rule: seq(hour12, char(":."), minutelz, spaceOpt, meridian),
+		name: "timeShort12",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenHour | givenMinute
 
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -262,9 +306,10 @@ func formats() []format {
 	}
 
 	timeTiny12 := format{
-		regex: "^" + reHour12 + reSpaceOpt + reMeridian,
-		name:  "timeTiny12",
+		rule: seq(hour12, spaceOpt, meridian),
+		name: "timeTiny12",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenHour
 
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -278,9 +323,10 @@ func formats() []format {
 	}
 
 	soap := format{
-		regex: "^" + reYear4 + "-" + reMonthlz + "-" + reDaylz + "T" + reHour24lz + ":" + reMinutelz + ":" + reSecondlz + reFrac + "(z|Z)?" + reTzCorrection + "?",
-		name:  "soap",
+		rule: seq(year4, char("-"), monthlz, char("-"), daylz, word("t"), hour24lz, char(":"), minutelz, char(":"), secondlz, frac, opt(group(word("z"))), opt(tzCorrection)),
+		name: "soap",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate | givenClock | givenFraction
 
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -309,13 +355,7 @@ func formats() []format {
 				return err
 			}
 
-			mili := inputs[6]
-
-			if len(mili) > 3 {
-				mili = mili[0:3]
-			}
-
-			frac, err := strconv.Atoi(mili)
+			frac, err := processFraction(inputs[6])
 			if err != nil {
 				return err
 			}
@@ -338,9 +378,10 @@ func formats() []format {
 	}
 
 	wddx := format{
-		regex: "^" + reYear4 + "-" + reMonth + "-" + reDay + "T" + reHour24 + ":" + reMinute + ":" + reSecond,
-		name:  "wddx",
+		rule: seq(year4, char("-"), month, char("-"), day, word("t"), hour24, char(":"), minute, char(":"), second),
+		name: "wddx",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate | givenClock
 
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -380,9 +421,10 @@ func formats() []format {
 	}
 
 	exif := format{
-		regex: "(?i)" + "^" + reYear4 + ":" + reMonthlz + ":" + reDaylz + " " + reHour24lz + ":" + reMinutelz + ":" + reSecondlz,
-		name:  "exif",
+		rule: seq(year4, char(":"), monthlz, char(":"), daylz, char(" "), hour24lz, char(":"), minutelz, char(":"), secondlz),
+		name: "exif",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate | givenClock
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -421,9 +463,10 @@ func formats() []format {
 	}
 
 	xmlRpc := format{
-		regex: "^" + reYear4 + reMonthlz + reDaylz + "T" + reHour24 + ":" + reMinutelz + ":" + reSecondlz,
-		name:  "xmlrpc",
+		rule: seq(year4, monthlz, daylz, word("t"), hour24, char(":"), minutelz, char(":"), secondlz),
+		name: "xmlrpc",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate | givenClock
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -462,9 +505,10 @@ func formats() []format {
 	}
 
 	xmlRpcNoColon := format{
-		regex: "^" + reYear4 + reMonthlz + reDaylz + "[Tt]" + reHour24 + reMinutelz + reSecondlz,
-		name:  "xmlrpcnocolon",
+		rule: seq(year4, monthlz, daylz, word("t"), hour24, minutelz, secondlz),
+		name: "xmlrpcnocolon",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate | givenClock
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -503,9 +547,10 @@ func formats() []format {
 	}
 
 	clf := format{
-		regex: "(?i)^" + reDay + "/(" + reMonthAbbr + ")/" + reYear4 + ":" + reHour24lz + ":" + reMinutelz + ":" + reSecondlz + reSpace + reTzCorrection,
-		name:  "clf",
+		rule: seq(day, char("/"), group(word(monthAbbr...)), char("/"), year4, char(":"), hour24lz, char(":"), minutelz, char(":"), secondlz, space, tzCorrection),
+		name: "clf",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate | givenClock
 
 			day, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -552,9 +597,10 @@ func formats() []format {
 	}
 
 	iso8601long := format{
-		regex: "^[Tt]?" + reHour24 + "[:.]" + reMinute + "[:.]" + reSecond + reFrac,
-		name:  "iso8601long",
+		rule: seq(opt(word("t")), hour24, char(":."), minute, char(":."), second, frac),
+		name: "iso8601long",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenClock | givenFraction
 
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -571,13 +617,7 @@ func formats() []format {
 				return err
 			}
 
-			mili := inputs[3]
-
-			if len(mili) > 3 {
-				mili = mili[0:3]
-			}
-
-			frac, err := strconv.Atoi(mili)
+			frac, err := processFraction(inputs[3])
 			if err != nil {
 				return err
 			}
@@ -586,9 +626,10 @@ func formats() []format {
 	}
 
 	dateTextual := format{
-		regex: "(?i)^" + reMonthText + "[ .\\t-]*" + reDay + "[,.stndrh\\t ]+" + reYear,
-		name:  "datetextual",
+		rule: seq(monthText, run(" .\t-", 0), day, run(",.stndrh\t ", 1), year),
+		name: "datetextual",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 
 			month := inputs[0]
 
@@ -608,9 +649,10 @@ func formats() []format {
 	}
 
 	pointedDate4 := format{
-		regex: "^" + reDay + "[.\\t-]" + reMonth + "[.-]" + reYear4,
-		name:  "pointeddate4",
+		rule: seq(day, char(".\t-"), month, char(".-"), year4),
+		name: "pointeddate4",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 			day, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -631,9 +673,10 @@ func formats() []format {
 	}
 
 	pointedDate2 := format{
-		regex: "^" + reDay + "[.\\t]" + reMonth + "\\." + reYear2,
-		name:  "pointeddate2",
+		rule: seq(day, char(".\t"), month, char("."), year2),
+		name: "pointeddate2",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 			day, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -654,9 +697,10 @@ func formats() []format {
 	}
 
 	timeLong24 := format{
-		regex: "^t?" + reHour24 + "[:.]" + reMinute + "[:.]" + reSecond,
-		name:  "timelong24",
+		rule: seq(opt(word("t")), hour24, char(":."), minute, char(":."), second),
+		name: "timelong24",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenClock
 
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -679,9 +723,10 @@ func formats() []format {
 	}
 
 	dateNoColon := format{
-		regex: "^" + reYear4 + reMonthlz + reDaylz,
-		name:  "datenocolon",
+		rule: seq(year4, monthlz, daylz),
+		name: "datenocolon",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -704,9 +749,10 @@ func formats() []format {
 	}
 
 	pgydotd := format{ //also known as julian date format
-		regex: "^" + reYear4 + `\.?` + reDayOfYear,
-		name:  "pgydotd",
+		rule: seq(year4, opt(char(".")), dayOfYear),
+		name: "pgydotd",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -721,9 +767,10 @@ func formats() []format {
 	}
 
 	timeShort24 := format{
-		regex: "^t?" + reHour24 + "[:.]" + reMinute,
-		name:  "timeshort24",
+		rule: seq(opt(word("t")), hour24, char(":."), minute),
+		name: "timeshort24",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenHour | givenMinute
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -738,9 +785,10 @@ func formats() []format {
 	}
 
 	iso8601noColon := format{
-		regex: "^t?" + reHour24lz + reMinutelz + reSecondlz,
-		name:  "iso8601nocolon",
+		rule: seq(opt(word("t")), hour24lz, minutelz, secondlz),
+		name: "iso8601nocolon",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenClock
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -761,9 +809,10 @@ func formats() []format {
 	}
 
 	dateSlash := format{
-		regex: "^" + reYear4 + "/" + reMonth + "/" + reDay + "/?",
-		name:  "dateslash",
+		rule: seq(year4, char("/"), month, char("/"), day, opt(char("/"))),
+		name: "dateslash",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -783,9 +832,10 @@ func formats() []format {
 	}
 
 	american := format{
-		regex: "^" + reMonth + "/" + reDay + "/" + reYear,
-		name:  "american",
+		rule: seq(month, char("/"), day, char("/"), year),
+		name: "american",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 			month, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -805,9 +855,10 @@ func formats() []format {
 	}
 
 	americanShort := format{
-		regex: "^" + reMonth + "/" + reDay,
-		name:  "americanshort",
+		rule: seq(month, char("/"), day),
+		name: "americanshort",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenMonth | givenDay
 			month, err := strconv.Atoi(inputs[0])
 			if err != nil {
 				return err
@@ -832,9 +883,10 @@ func formats() []format {
 
 	gnuDateShortOrIso8601date2 := format{
 		// iso8601date2 is complete subset of gnudateshort
-		regex: "^" + reYear + "-" + reMonth + "-" + reDay,
-		name:  "gnudateshort | iso8601date2",
+		rule: seq(year, char("-"), month, char("-"), day),
+		name: "gnudateshort | iso8601date2",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 			year, err := processYear(inputs[0])
 
 			if err != nil {
@@ -856,9 +908,10 @@ func formats() []format {
 	}
 
 	iso8601date4 := format{
-		regex: "^" + reYear4withSign + "-" + reMonthlz + "-" + reDaylz,
-		name:  "iso8601date4",
+		rule: seq(year4withSign, char("-"), monthlz, char("-"), daylz),
+		name: "iso8601date4",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 			year, err := strconv.Atoi(inputs[0])
 
 			if err != nil {
@@ -879,9 +932,10 @@ func formats() []format {
 	}
 
 	gnuNoColon := format{
-		regex: "^t" + reHour24lz + reMinutelz,
-		name:  "gnunocolon",
+		rule: seq(word("t"), hour24lz, minutelz),
+		name: "gnunocolon",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenHour | givenMinute
 			hour, err := strconv.Atoi(inputs[0])
 
 			if err != nil {
@@ -906,9 +960,10 @@ func formats() []format {
 	}
 
 	gnuDateShorter := format{
-		regex: "^" + reYear4 + "-" + reMonth,
-		name:  "gnudateshorter",
+		rule: seq(year4, char("-"), month),
+		name: "gnudateshorter",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenYear | givenMonth
 			year, err := strconv.Atoi(inputs[0])
 
 			if err != nil {
@@ -926,9 +981,10 @@ func formats() []format {
 	pgTextReverse := format{
 		// note: allowed years are from 32-9999
 		// years below 32 should be treated as days in datefull
-		regex: "(?i)^" + `(\d{3,4}|[4-9]\d|3[2-9])-(` + reMonthAbbr + ")-" + reDaylz,
-		name:  "pgtextreverse",
+		rule: seq(group(digits(2, 4, func(d string) bool { return len(d) > 2 || d >= "32" })), char("-"), group(word(monthAbbr...)), char("-"), daylz),
+		name: "pgtextreverse",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 			year, err := processYear(inputs[0])
 
 			if err != nil {
@@ -951,9 +1007,10 @@ func formats() []format {
 	}
 
 	dateFull := format{
-		regex: "(?i)^" + reDay + `[ \t.-]*` + reMonthText + `[ \t.-]*` + reYear,
-		name:  "datefull",
+		rule: seq(day, run(" \t.-", 0), monthText, run(" \t.-", 0), year),
+		name: "datefull",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 
 			day, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -973,9 +1030,10 @@ func formats() []format {
 	}
 
 	dateNoDay := format{
-		regex: "(?i)^" + reMonthText + `[ .\t-]*` + reYear4,
-		name:  "datenoday",
+		rule: seq(monthText, run(" .\t-", 0), year4),
+		name: "datenoday",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenYear | givenMonth
 			month := lookupMonth(inputs[0])
 
 			year, err := processYear(inputs[1])
@@ -989,9 +1047,10 @@ func formats() []format {
 	}
 
 	dateNoDayRev := format{
-		regex: "(?i)^" + reYear4 + `[ .\t-]*` + reMonthText,
-		name:  "datenodayrev",
+		rule: seq(year4, run(" .\t-", 0), monthText),
+		name: "datenodayrev",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenYear | givenMonth
 			year, err := processYear(inputs[0])
 
 			if err != nil {
@@ -1005,9 +1064,10 @@ func formats() []format {
 	}
 
 	pgTextShort := format{
-		regex: "(?i)^(" + reMonthAbbr + ")-" + reDaylz + "-" + reYear,
-		name:  "pgtextshort",
+		rule: seq(group(word(monthAbbr...)), char("-"), daylz, char("-"), year),
+		name: "pgtextshort",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 
 			month := lookupMonth(inputs[0])
 
@@ -1026,10 +1086,33 @@ func formats() []format {
 		},
 	}
 
+	dayOfMonth := format{
+		rule: seq(opt(seq(word("the"), space)), dayNumber, daySuffix),
+		name: "dayofmonth",
+		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDay
+
+			if r.dates > 0 {
+				return fmt.Errorf("strtotime: The string contains two conflicting date/months")
+			}
+
+			r.dates++
+
+			day, err := strconv.Atoi(inputs[0])
+			if err != nil {
+				return err
+			}
+
+			r.d = pointer(day)
+			return nil
+		},
+	}
+
 	dateNoYear := format{
-		regex: "(?i)^" + reMonthText + `[ .\t-]*` + reDay + `[,.stndrh\t ]*`,
-		name:  "datenoyear",
+		rule: seq(monthText, run(" .\t-", 0), day, run(",.stndrh\t ", 0)),
+		name: "datenoyear",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenMonth | givenDay
 
 			if r.dates > 0 {
 				return fmt.Errorf("strtotime: The string contains two conflicting date/months")
@@ -1051,9 +1134,10 @@ func formats() []format {
 	}
 
 	dateNoYearRev := format{
-		regex: "(?i)^" + reDay + `[ .\t-]*` + reMonthText,
-		name:  "datenoyearrev",
+		rule: seq(day, run(" .\t-", 0), monthText),
+		name: "datenoyearrev",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenMonth | givenDay
 
 			if r.dates > 0 {
 				return fmt.Errorf("strtotime: The string contains two conflicting date/months")
@@ -1074,13 +1158,14 @@ func formats() []format {
 	}
 
 	isoWeekDay := format{
-		regex: "^" + reYear4 + "-?W" + reWeekOfYear + `(?:-?([0-7]))?`,
-		name:  "isoweekday",
+		rule: seq(year4, opt(char("-")), word("w"), weekOfYear, opt(seq(opt(char("-")), group(digits(1, 1, between(0, 7)))))),
+		name: "isoweekday",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDate
 
 			day := 1
 
-			if len(inputs) > 2 {
+			if inputs[2] != "" {
 				d, err := strconv.Atoi(inputs[2])
 				if err != nil {
 					return err
@@ -1123,121 +1208,81 @@ func formats() []format {
 		},
 	}
 
+	relativeTextMonth := format{
+		rule: seq(group(word(relTextText...)), space, group(word(concat(monthFull, monthAbbr)...))),
+		name: "relativetextmonth",
+		callback: func(r *result, inputs ...string) error {
+			r.given |= givenMonth
+			if r.dates > 0 {
+				return fmt.Errorf("strtotime: The string contains two conflicting date/months")
+			}
+			r.dates++
+			r.m = pointer(lookupMonth(inputs[1]))
+
+			switch strings.ToLower(inputs[0]) {
+			case "next":
+				r.relativeMonth = 1
+			case "last", "previous":
+				r.relativeMonth = -1
+			}
+			return nil
+		},
+	}
+
 	relativeText := format{
-		regex: "(?i)^(" + reReltextnumber + "|" + reReltexttext + ")" + reSpace + "(" + reReltextunit + ")",
-		name:  "relativetext",
+		rule: seq(group(word(concat(relTextNumber, relTextText)...)), space, group(word(relTextUnit...))),
+		name: "relativetext",
 		callback: func(r *result, inputs ...string) error {
 			relValue := inputs[0]
 			relUnit := inputs[1]
 			//TODO: implement handling of 'this time-unit'
 			amount, _ := lookupRelative(relValue)
+			ok := r.shiftBy(amount, relUnit)
 
-			switch strings.ToLower(relUnit) {
-			case "sec", "secs", "second", "seconds":
-				r.rs += amount
-				break
-			case "min", "mins", "minute", "minutes":
-				r.ri += amount
-				break
-			case "hour", "hours":
-				r.rh += amount
-				break
-			case "day", "days":
-				r.rd += amount
-				break
-			case "fortnight", "fortnights", "forthnight", "forthnights":
-				r.rd += amount * 14
-				break
-			case "week", "weeks":
-				r.rd += amount * 7
-				break
-			case "month", "months":
-				r.rm += amount
-				break
-			case "year", "years":
-				r.ry += amount
-				break
-			case "mon", "monday", "tue", "tuesday", "wed", "wednesday", "thu", "thursday", "fri", "friday", "sat", "saturday", "sun", "sunday":
-				r.resetTime()
-				r.weekday = pointer(lookupWeekday(relUnit, 7))
-				r.weekdayBehavior = 1
-				if amount > 0 {
-					r.rd += (amount - 1) * 7
-				}
-				if amount <= 0 {
-					r.rd += amount * 7
-				}
-				break
-			case "weekday", "weekdays":
-				//TODO: Implement
-				break
+			if !ok {
+				return &RangeError{Value: relValue + " " + relUnit}
+			}
+
+			// "this month" doesn't shift by anything
+			if unit, ok := relativeUnits[strings.ToLower(relUnit)]; ok && amount != 0 {
+				r.shifted(unit.freq)
 			}
 			return nil
 		},
 	}
 
 	relative := format{
-		regex: "(?i)^([+-]*)[ \\t]*(\\d+)" + reSpaceOpt + "(" + reReltextunit + "|week)",
-		name:  "relative",
+		rule: seq(group(run("+-", 0)), run(" \t", 0), group(digits(1, anyLength, nil)), spaceOpt, group(word(concat(relTextUnit, []string{"week"})...))),
+		name: "relative",
 		callback: func(r *result, inputs ...string) error {
 			signs := inputs[0]
 
 			relValue, err := strconv.Atoi(inputs[1])
 			if err != nil {
-				return err
+				return &RangeError{Value: inputs[1]}
 			}
 			relUnit := inputs[2]
 			minuses := float64(strings.Count(signs, "-"))
 			amount := relValue * int(math.Pow(float64(-1), minuses))
+			ok := r.shiftBy(amount, relUnit)
 
-			switch strings.ToLower(relUnit) {
-			case "sec", "secs", "second", "seconds":
-				r.rs += amount
-				break
-			case "min", "mins", "minute", "minutes":
-				r.ri += amount
-				break
-			case "hour", "hours":
-				r.rh += amount
-				break
-			case "day", "days":
-				r.rd += amount
-				break
-			case "fortnight", "fortnights", "forthnight", "forthnights":
-				r.rd += amount * 14
-				break
-			case "week", "weeks":
-				r.rd += amount * 7
-				break
-			case "month", "months":
-				r.rm += amount
-				break
-			case "year", "years":
-				r.ry += amount
-				break
-			case "mon", "monday", "tue", "tuesday", "wed", "wednesday", "thu", "thursday", "fri", "friday", "sat", "saturday", "sun", "sunday":
-				r.resetTime()
-				r.weekday = pointer(lookupWeekday(relUnit, 7))
-				r.weekdayBehavior = 1
-				rd := amount * 7
-				if amount > 0 {
-					rd = (amount - 1) * 7
-				}
-				r.rd += rd
-				break
-			case "weekday", "weekdays":
-				// todo
-				break
+			if !ok {
+				return &RangeError{Value: signs + inputs[1] + " " + relUnit}
+			}
+
+			if unit, ok := relativeUnits[strings.ToLower(relUnit)]; ok {
+				r.shifted(unit.freq)
 			}
 			return nil
 		},
 	}
 
 	dayText := format{
-		regex: "(?i)^(" + reDaytext + ")",
-		name:  "daytext",
+		rule: group(word(dayText...)),
+		name: "daytext",
 		callback: func(r *result, inputs ...string) error {
-			r.resetTime()
+			r.given |= givenWeekday
+			r.resetDayTime()
 			r.weekday = pointer(lookupWeekday(inputs[0], 0))
 
 			if r.weekdayBehavior != 2 {
@@ -1248,10 +1293,11 @@ func formats() []format {
 	}
 
 	relativeTextWeek := format{
-		regex: "(?i)^(" + reReltexttext + ")" + reSpace + "week",
-		name:  "relativetextweek",
+		rule: seq(group(word(relTextText...)), space, word("week")),
+		name: "relativetextweek",
 		callback: func(r *result, inputs ...string) error {
 			r.weekdayBehavior = 2
+			r.shifted(Weekly)
 
 			switch strings.ToLower(inputs[0]) {
 			case "this":
@@ -1265,17 +1311,42 @@ func formats() []format {
 				break
 			}
 
-			if r.weekday == nil {
-				r.weekday = pointer(1)
+			// with no weekday, the week's first day is meant, which toDate works out
+			return nil
+		},
+	}
+
+	startOrEndOfWeek := format{
+		rule: seq(group(word("start", "beginning", "end")), space, word("of"), opt(seq(space, word("the"))), space, opt(seq(group(word(relTextText...)), space)), word("week")),
+		name: "startofweek | endofweek",
+		callback: func(r *result, inputs ...string) error {
+			r.given |= givenDay
+			r.shifted(Weekly)
+			r.weekdayBehavior = 2
+			r.resetTime()
+
+			switch strings.ToLower(inputs[1]) {
+			case "next":
+				r.rd += 7
+			case "last", "previous":
+				r.rd -= 7
+			}
+
+			if strings.ToLower(inputs[0]) == "end" {
+				r.endOfWeek = true
+				r.h = pointer(23)
+				r.i = pointer(59)
+				r.s = pointer(59)
 			}
 			return nil
 		},
 	}
 
 	monthFullOrMonthAbbr := format{
-		regex: "(?i)^(" + reMonthFull + "|" + reMonthAbbr + ")",
-		name:  "monthfull | monthabbr",
+		rule: group(word(concat(monthFull, monthAbbr)...)),
+		name: "monthfull | monthabbr",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenMonth
 			month := inputs[0]
 			if r.dates > 0 {
 				return fmt.Errorf("strtotime: The string contains two conflicting date/months")
@@ -1287,16 +1358,24 @@ func formats() []format {
 	}
 
 	tzCorrection := format{
-		regex: "(?i)^" + reTzCorrection,
-		name:  "tzcorrection",
+		rule: tzCorrection,
+		name: "tzcorrection",
 		callback: func(r *result, inputs ...string) error {
 			return r.zone(processTzCorrection(inputs[0], 0))
 		},
 	}
 
+	utc := format{
+		rule: seq(word("z", "utc", "gmt"), wordEnd()),
+		name: "utc",
+		callback: func(r *result, inputs ...string) error {
+			return r.zone(0)
+		},
+	}
+
 	ago := format{
-		regex: "(?i)^ago",
-		name:  "ago",
+		rule: word("ago"),
+		name: "ago",
 		callback: func(r *result, inputs ...string) error {
 			r.ry = -r.ry
 			r.rm = -r.rm
@@ -1314,9 +1393,10 @@ func formats() []format {
 		// it's down here, because it is very generic (4 digits in a row)
 		// thus conflicts with many rules above
 		// only year4 should come afterwards
-		regex: "(?i)^" + reHour24lz + reMinutelz,
-		name:  "gnunocolon",
+		rule: seq(hour24lz, minutelz),
+		name: "gnunocolon",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenHour | givenMinute
 
 			hour, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -1339,9 +1419,10 @@ func formats() []format {
 	}
 
 	year4 := format{
-		regex: "^" + reYear4,
-		name:  "year4",
+		rule: year4,
+		name: "year4",
 		callback: func(r *result, inputs ...string) error {
+			r.given |= givenYear
 
 			year, err := strconv.Atoi(inputs[0])
 			if err != nil {
@@ -1354,9 +1435,17 @@ func formats() []format {
 		},
 	}
 
+	filler := format{
+		rule: seq(word("at", "in", "on"), wordEnd()),
+		name: "filler",
+		callback: func(r *result, inputs ...string) error {
+			return nil
+		},
+	}
+
 	whitespace := format{
-		regex: "^[ .,\t]+",
-		name:  "whitespace",
+		rule: run(" .,\t", 1),
+		name: "whitespace",
 		callback: func(r *result, inputs ...string) error {
 			return nil
 		},
@@ -1369,7 +1458,9 @@ func formats() []format {
 		midnightOrToday,
 		tomorrow,
 		timestamp,
+		namedEpoch,
 		firstOrLastDay,
+		startOrEndOfWeek,
 		backOrFrontOf,
 		// weekdayOf,
 		mssqltime,
@@ -1405,18 +1496,36 @@ func formats() []format {
 		pgTextShort,
 		dateNoYear,
 		dateNoYearRev,
+		dayOfMonth,
 		isoWeekDay,
+		relativeTextMonth,
 		relativeText,
 		relative,
 		dayText,
 		relativeTextWeek,
 		monthFullOrMonthAbbr,
 		tzCorrection,
+		utc,
 		ago,
 		gnuNoColon2,
 		year4,
+		filler,
 		whitespace,
 	}
 
 	return formats
 }
+
+// withBareTimestamps adds a format reading the numbers of ten digits or more that no other format reads as
+// Unix timestamps, as BareTimestamps asks, to the end of formats
+func withBareTimestamps(formats []format) []format {
+	bareTimestamp := format{
+		rule: seq(group(seq(opt(char("-")), digits(10, 19, nil))), opt(frac)),
+		name: "baretimestamp",
+		callback: func(r *result, inputs ...string) error {
+			return r.since(epoch{origin: unix, unit: guessUnit(inputs[0])}, inputs[0], inputs[1])
+		},
+	}
+
+	return append(formats[:len(formats):len(formats)], bareTimestamp)
+}
@@ -0,0 +1,528 @@
+package strtotime
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind is the kind of a token, and kinds a set of them
+type tokenKind int
+
+type kinds int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenWord
+	tokenSpace
+	tokenPunct
+)
+
+// token is a run of digits, of letters or of white space, or any other single character
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+}
+
+// input is a lexed string, with the token covering each of its bytes, and the start and end
+// of each group of the rule being matched, or -1 for groups that matched nothing
+type input struct {
+	s       string
+	tokens  []token
+	tokenAt []int
+	groups  []int
+}
+
+// lex splits s into tokens. The suffix of a day run into a month, as in "1stjanuary", is a token of its own.
+func lex(s string) *input {
+	in := &input{s: s, tokenAt: make([]int, len(s))}
+
+	for pos := 0; pos < len(s); {
+		c, size := utf8.DecodeRuneInString(s[pos:])
+		kind := kindOfRune(c)
+		end := pos + size
+
+		for kind != tokenPunct && end < len(s) {
+			next, size := utf8.DecodeRuneInString(s[end:])
+			if kindOfRune(next) != kind {
+				break
+			}
+			end += size
+		}
+
+		if kind == tokenWord && pos > 0 && kindOfRune(rune(s[pos-1])) == tokenNumber && gluedDaySuffix(s[pos:end]) {
+			in.add(tokenWord, pos, pos+2)
+			pos += 2
+		}
+
+		in.add(kind, pos, end)
+		pos = end
+	}
+
+	return in
+}
+
+// add appends the token running from pos to end
+func (in *input) add(kind tokenKind, pos, end int) {
+	for i := pos; i < end; i++ {
+		in.tokenAt[i] = len(in.tokens)
+	}
+
+	in.tokens = append(in.tokens, token{kind, pos, in.s[pos:end]})
+}
+
+// gluedDaySuffix reports whether a word that follows a number is the suffix of a day and a month run
+// together, as in "1stjanuary", which lex splits in two
+func gluedDaySuffix(w string) bool {
+	w = strings.ToLower(w)
+
+	switch {
+	case len(w) <= 2:
+		return false
+	case strings.HasPrefix(w, "st"), strings.HasPrefix(w, "nd"), strings.HasPrefix(w, "rd"), strings.HasPrefix(w, "th"):
+	default:
+		return false
+	}
+
+	for _, month := range concat(monthFull, monthAbbr) {
+		if w[2:] == month {
+			return true
+		}
+	}
+
+	return false
+}
+
+// set returns the set of only this kind
+func (k tokenKind) set() kinds {
+	return 1 << uint(k)
+}
+
+func kindOfRune(c rune) tokenKind {
+	switch {
+	case c >= '0' && c <= '9':
+		return tokenNumber
+	case unicode.IsLetter(c):
+		return tokenWord
+	case unicode.IsSpace(c):
+		return tokenSpace
+	}
+
+	return tokenPunct
+}
+
+// at returns the token at pos, and whether pos is where it starts
+func (in *input) at(pos int) (*token, bool) {
+	if pos >= len(in.s) {
+		return nil, false
+	}
+
+	t := &in.tokens[in.tokenAt[pos]]
+
+	return t, t.pos == pos
+}
+
+// rule is a piece of the grammar. Like a regular expression, it matches text at a position,
+// trying its alternatives in order and backtracking when what follows it fails to match:
+// match records its groups from the group numbered base on, and calls k with the position it
+// got to, for as long as k rejects it and alternatives remain.
+type rule struct {
+	match func(in *input, pos, base int, k func(pos int) bool) bool
+
+	// ends, for rules that don't nest backtracking ones, returns the ith position the rule can
+	// get to, in the order they are tried, recording its groups, or false once there are no
+	// more. It lets sequences backtrack over them without continuations.
+	ends func(in *input, pos, base, i int) (int, bool)
+
+	// the kinds of token the rule can start with, whether it can match nothing,
+	// and the number of groups it captures
+	starts kinds
+	empty  bool
+	groups int
+}
+
+// parse matches the rule at pos, returning the matched text followed by its groups, as
+// regexp's FindStringSubmatch does. The match must end where a token does, and it returns
+// nil if there is no such match.
+func (r rule) parse(in *input, pos int) []string {
+	var match []string
+
+	groups := r.groups
+
+	if cap(in.groups) < 2*groups {
+		in.groups = make([]int, 2*groups)
+	}
+	in.groups = in.groups[:2*groups]
+
+	r.match(in, pos, 0, func(end int) bool {
+		if _, start := in.at(end); end == pos || !start && !tokenBoundary(in.s, end) {
+			return false
+		}
+		match = append(make([]string, 0, groups+1), in.s[pos:end])
+		for i := 0; i < groups; i++ {
+			if start := in.groups[2*i]; start >= 0 {
+				match = append(match, in.s[start:in.groups[2*i+1]])
+			} else {
+				match = append(match, "")
+			}
+		}
+		return true
+	})
+
+	return match
+}
+
+// withEnds completes a rule that matches at the positions ends returns
+func withEnds(r rule, ends func(in *input, pos, base, i int) (int, bool)) rule {
+	r.ends = ends
+	r.match = func(in *input, pos, base int, k func(int) bool) bool {
+		for i := 0; ; i++ {
+			end, ok := ends(in, pos, base, i)
+			if !ok {
+				return false
+			}
+			if k(end) {
+				return true
+			}
+		}
+	}
+
+	return r
+}
+
+// seq matches each of the rules, one after the other
+func seq(rules ...rule) rule {
+	r := rule{empty: true}
+	bases := make([]int, len(rules))
+
+	for i, sub := range rules {
+		if r.empty {
+			r.starts |= sub.starts
+			r.empty = sub.empty
+		}
+		bases[i] = r.groups
+		r.groups += sub.groups
+	}
+
+	var matchFrom func(i int, in *input, pos, base int, k func(int) bool) bool
+	matchFrom = func(i int, in *input, pos, base int, k func(int) bool) bool {
+		if i == len(rules) {
+			return k(pos)
+		}
+		sub := rules[i]
+		if sub.ends == nil {
+			return sub.match(in, pos, base+bases[i], func(pos int) bool {
+				return matchFrom(i+1, in, pos, base, k)
+			})
+		}
+		for j := 0; ; j++ {
+			end, ok := sub.ends(in, pos, base+bases[i], j)
+			if !ok {
+				return false
+			}
+			if matchFrom(i+1, in, end, base, k) {
+				return true
+			}
+		}
+	}
+
+	r.match = func(in *input, pos, base int, k func(int) bool) bool {
+		return matchFrom(0, in, pos, base, k)
+	}
+
+	return r
+}
+
+// alt matches the first of the rules that lets what follows match. The rules must capture
+// the same number of groups.
+func alt(rules ...rule) rule {
+	r := rule{groups: rules[0].groups}
+
+	for _, sub := range rules {
+		r.starts |= sub.starts
+		r.empty = r.empty || sub.empty
+	}
+
+	r.match = func(in *input, pos, base int, k func(int) bool) bool {
+		for _, sub := range rules {
+			if sub.match(in, pos, base, k) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return r
+}
+
+// opt matches the rule, or nothing, in which case its groups are empty
+func opt(sub rule) rule {
+	r := rule{
+		starts: sub.starts,
+		empty:  true,
+		groups: sub.groups,
+	}
+
+	skip := func(in *input, pos, base int) int {
+		for i := 2 * base; i < 2*(base+sub.groups); i++ {
+			in.groups[i] = -1
+		}
+		return pos
+	}
+
+	if sub.ends != nil {
+		return withEnds(r, func(in *input, pos, base, i int) (int, bool) {
+			if end, ok := sub.ends(in, pos, base, i); ok {
+				return end, true
+			}
+			if i == 0 {
+				return skip(in, pos, base), true
+			}
+			if _, ok := sub.ends(in, pos, base, i-1); ok {
+				return skip(in, pos, base), true
+			}
+			return 0, false
+		})
+	}
+
+	r.match = func(in *input, pos, base int, k func(int) bool) bool {
+		return sub.match(in, pos, base, k) || k(skip(in, pos, base))
+	}
+
+	return r
+}
+
+// group captures the text the rule matches. It comes before the groups nested in it.
+func group(sub rule) rule {
+	r := rule{
+		starts: sub.starts,
+		empty:  sub.empty,
+		groups: sub.groups + 1,
+	}
+
+	if sub.ends != nil {
+		return withEnds(r, func(in *input, pos, base, i int) (int, bool) {
+			end, ok := sub.ends(in, pos, base+1, i)
+			if ok {
+				in.groups[2*base] = pos
+				in.groups[2*base+1] = end
+			}
+			return end, ok
+		})
+	}
+
+	r.match = func(in *input, pos, base int, k func(int) bool) bool {
+		return sub.match(in, pos, base+1, func(end int) bool {
+			in.groups[2*base] = pos
+			in.groups[2*base+1] = end
+			return k(end)
+		})
+	}
+
+	return r
+}
+
+// word matches a whole word that is one of the given words, ignoring case
+func word(words ...string) rule {
+	set := map[string]bool{}
+
+	for _, w := range words {
+		set[w] = true
+	}
+
+	return withEnds(rule{starts: tokenWord.set()}, func(in *input, pos, base, i int) (int, bool) {
+		t, start := in.at(pos)
+		if i > 0 || !start || t.kind != tokenWord || !set[strings.ToLower(t.text)] {
+			return 0, false
+		}
+		return pos + len(t.text), true
+	})
+}
+
+// wordEnd matches nothing, where no word or number goes on from the last character, as \b does
+func wordEnd() rule {
+	return withEnds(rule{empty: true}, func(in *input, pos, base, i int) (int, bool) {
+		t, _ := in.at(pos)
+		return pos, i == 0 && (t == nil || (t.kind != tokenWord && t.kind != tokenNumber))
+	})
+}
+
+// alone matches the rule where white space or an end of the string comes both before and after it
+func alone(sub rule) rule {
+	before := withEnds(rule{empty: true}, func(in *input, pos, base, i int) (int, bool) {
+		return pos, i == 0 && (pos == 0 || in.tokens[in.tokenAt[pos-1]].kind == tokenSpace)
+	})
+
+	after := withEnds(rule{empty: true}, func(in *input, pos, base, i int) (int, bool) {
+		t, _ := in.at(pos)
+		return pos, i == 0 && (t == nil || t.kind == tokenSpace)
+	})
+
+	return seq(before, sub, after)
+}
+
+// char matches one of the given characters. Letters and digits are not characters of their own,
+// and can't be matched by it.
+func char(chars string) rule {
+	r := rule{}
+
+	for _, c := range chars {
+		r.starts |= kindOfRune(c).set()
+	}
+
+	return withEnds(r, func(in *input, pos, base, i int) (int, bool) {
+		if i > 0 || pos >= len(in.s) {
+			return 0, false
+		}
+		c, size := utf8.DecodeRuneInString(in.s[pos:])
+		if kindOfRune(c) == tokenNumber || kindOfRune(c) == tokenWord || !strings.ContainsRune(chars, c) {
+			return 0, false
+		}
+		return pos + size, true
+	})
+}
+
+// run matches at least min tokens, as many as it can, made up only of the given characters
+func run(chars string, min int) rule {
+	r := rule{empty: min == 0}
+
+	for _, c := range chars {
+		r.starts |= kindOfRune(c).set()
+	}
+
+	return withEnds(r, func(in *input, pos, base, i int) (int, bool) {
+		var ends []int
+
+		for end := pos; ; {
+			ends = append(ends, end)
+			t, start := in.at(end)
+			if !start || t.kind == tokenNumber || strings.Trim(t.text, chars) != "" {
+				break
+			}
+			end += len(t.text)
+		}
+
+		if n := len(ends) - 1 - i; n >= min {
+			return ends[n], true
+		}
+		return 0, false
+	})
+}
+
+// digits matches from min to max digits, as many as it can, that valid accepts. A number may be
+// split between rules, as in "20081031", and digits may start where another rule left off.
+func digits(min, max int, valid func(d string) bool) rule {
+	return withEnds(rule{starts: tokenNumber.set()}, func(in *input, pos, base, i int) (int, bool) {
+		t, _ := in.at(pos)
+		if t == nil || t.kind != tokenNumber {
+			return 0, false
+		}
+		available := t.pos + len(t.text) - pos
+		if available > max {
+			available = max
+		}
+		for n := available; n >= min; n-- {
+			if valid == nil || valid(in.s[pos:pos+n]) {
+				if i == 0 {
+					return pos + n, true
+				}
+				i--
+			}
+		}
+		return 0, false
+	})
+}
+
+const anyLength = int(^uint(0) >> 1)
+
+// between returns a check that digits are a number from lo to hi
+func between(lo, hi int) func(string) bool {
+	return func(d string) bool {
+		n := 0
+		for _, c := range d {
+			n = n*10 + int(c-'0')
+		}
+		return n >= lo && n <= hi
+	}
+}
+
+// The pieces of the grammar the formats are made of. Those that check the digits they match spell out
+// the numbers they take as a regular expression would.
+var (
+	space    = run(" ", 1)
+	spaceOpt = run(" ", 0)
+	meridian = group(word("am", "pm"))
+
+	// 2[0-4]|[01]?[0-9]
+	hour24 = group(digits(1, 2, func(d string) bool { return len(d) == 1 || d[0] <= '1' || d <= "24" }))
+	// [01][0-9]|2[0-4]
+	hour24lz = group(digits(2, 2, between(0, 24)))
+	// 0?[1-9]|1[0-2]
+	hour12 = group(digits(1, 2, func(d string) bool { return d != "0" && d != "00" && (d[0] == '0' || between(1, 12)(d)) }))
+	// [0-5]?[0-9]
+	minute = group(digits(1, 2, func(d string) bool { return len(d) == 1 || d[0] <= '5' }))
+	// [0-5][0-9]
+	minutelz = group(digits(2, 2, func(d string) bool { return d[0] <= '5' }))
+	// 60|[0-5]?[0-9]
+	second = group(digits(1, 2, func(d string) bool { return len(d) == 1 || d[0] <= '5' || d == "60" }))
+	// 60|[0-5][0-9]
+	secondlz = group(digits(2, 2, func(d string) bool { return d[0] <= '5' || d == "60" }))
+	frac     = seq(char("."), group(digits(1, anyLength, nil)))
+
+	year          = group(digits(1, 4, nil))
+	year2         = group(digits(2, 2, nil))
+	year4         = group(digits(4, 4, nil))
+	year4withSign = group(seq(opt(char("+-")), digits(4, 4, nil)))
+	// 1[0-2]|0?[0-9]
+	month = group(digits(1, 2, func(d string) bool { return len(d) == 1 || d[0] == '0' || d <= "12" }))
+	// 0[0-9]|1[0-2]
+	monthlz = group(digits(2, 2, between(0, 12)))
+	// 3[01]|[0-2]?[0-9]
+	dayNumber = group(digits(1, 2, func(d string) bool { return len(d) == 1 || d[0] <= '2' || d <= "31" }))
+	daySuffix = word("st", "nd", "rd", "th")
+	// (3[01]|[0-2]?[0-9])(?:st|nd|rd|th)?
+	day = seq(dayNumber, opt(daySuffix))
+	// 0[0-9]|[1-2][0-9]|3[01]
+	daylz = group(digits(2, 2, between(0, 31)))
+
+	dayOfYear  = group(digits(3, 3, between(1, 366)))
+	weekOfYear = group(digits(2, 2, between(1, 53)))
+
+	tzCorrection = group(seq(opt(word("gmt")), group(char("+-")), hour24, opt(char(":")), opt(minute)))
+
+	dayFull       = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+	dayAbbr       = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	dayText       = concat(dayFull, dayAbbr, []string{"weekday", "weekdays"})
+	monthFull     = []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"}
+	monthAbbr     = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "sept", "oct", "nov", "dec"}
+	monthRoman    = []string{"i", "ii", "iii", "iv", "v", "vi", "vii", "viii", "ix", "x", "xi", "xii"}
+	monthText     = group(word(concat(monthFull, monthAbbr, monthRoman)...))
+	relTextNumber = []string{"first", "second", "third", "fourth", "fifth", "sixth", "seventh", "eight", "eighth", "ninth", "tenth", "eleventh", "twelfth"}
+	relTextText   = []string{"next", "last", "previous", "this"}
+	relTextUnit   = concat(plurals("second", "sec", "minute", "min", "hour", "day", "fortnight", "forthnight", "month", "quarter", "year"),
+		plurals("millisecond", "msec", "microsecond", "usec", "nanosecond", "nsec"), subSecondAbbr, []string{"weeks"}, dayText)
+	subSecondAbbr = []string{"ms", "us", "µs", "μs", "ns"}
+)
+
+// concat joins lists of words
+func concat(lists ...[]string) []string {
+	var words []string
+
+	for _, list := range lists {
+		words = append(words, list...)
+	}
+
+	return words
+}
+
+// plurals returns the words along with their plurals
+func plurals(words ...string) []string {
+	var all []string
+
+	for _, w := range words {
+		all = append(all, w, w+"s")
+	}
+
+	return all
+}
@@ -0,0 +1,325 @@
+package strtotime
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// traced returns the formats, recording each match of theirs in trace
+func traced(formats []format, trace *[]string) []format {
+	var t []format
+
+	for _, f := range formats {
+		f := f
+		callback := f.callback
+		f.callback = func(r *result, inputs ...string) error {
+			*trace = append(*trace, fmt.Sprintf("%v %q", f.name, inputs))
+			return callback(r, inputs...)
+		}
+		t = append(t, f)
+	}
+
+	return t
+}
+
+// grammarTests are read as the formats they list, matched with those inputs. Those that fail may match
+// formats before they do.
+var grammarTests = []struct {
+	in    string
+	steps []string
+	fails bool
+}{
+	{"", nil, false},
+	{" ", []string{`whitespace []`}, false},
+	{"\n", nil, true},
+	{"now ", []string{`now ["now"]`}, false},
+	{"  now", []string{`whitespace []`, `now ["now"]`}, false},
+	{"now\n", []string{`now ["now"]`}, false},
+	{"now, tomorrow", []string{`now ["now"]`, `whitespace []`, `tomorrow ["tomorrow"]`}, false},
+	{"nowhere", nil, true},
+	{"20081", nil, true},
+	{"@-86400", []string{`timestamp ["-86400" "" ""]`}, false},
+	{"@", nil, true},
+	{"@1569600000.123", []string{`timestamp ["1569600000" "123" ""]`}, false},
+	{"@1569600000123ms", []string{`timestamp ["1569600000123" "" "ms"]`}, false},
+	{"+500 ms", []string{`relative ["+" "500" "ms"]`}, false},
+	{"+500 msecs", []string{`relative ["+" "500" "msecs"]`}, false},
+	{"+500 mss", []string{`tzcorrection ["+500" "+" "5" "00"]`}, true},
+	{"10 µs ago", []string{`relative ["" "10" "µs"]`, `ago []`}, false},
+	{"next nanosecond", []string{`relativetext ["next" "nanosecond"]`}, false},
+	{"@1569600000000ms", []string{`timestamp ["1569600000000" "" "ms"]`}, false},
+	{"@1569600000000000µs", []string{`timestamp ["1569600000000000" "" "µs"]`}, false},
+	{"@1569600000000msec", []string{`timestamp ["1569600000000" "" ""]`}, true},
+	{"excel 45292.75", []string{`namedepoch ["excel" "45292" "75"]`}, false},
+	{"excel1904 43830", []string{`namedepoch ["excel1904" "43830" ""]`}, false},
+	{"excel1905 43830", nil, true},
+	{"jdn 2460311", []string{`namedepoch ["jdn" "2460311" ""]`}, false},
+	{"1569600000123", nil, true},
+	{"1569600000123.5", nil, true},
+	{"15696000001234567890", nil, true},
+	{"2008-08-07T18:11:31.0Z", []string{`soap ["2008" "08" "07" "18" "11" "31" "0" "Z" "" "" "" ""]`}, false},
+	{"2008-08-07 18:11:31 +02:00", []string{`gnudateshort | iso8601date2 ["2008" "08" "07"]`, `timelong24 ["18" "11" "31"]`, `tzcorrection ["+02:00" "+" "02" "00"]`}, false},
+	{"2008-W28-3", []string{`isoweekday ["2008" "28" "3"]`}, false},
+	{"2008-W28", []string{`isoweekday ["2008" "28" ""]`}, false},
+	{"2008W27", []string{`isoweekday ["2008" "27" ""]`}, false},
+	{"2008.197", []string{`pgydotd ["2008" "197"]`}, false},
+	{"31.12.2008", []string{`pointeddate4 ["31" "12" "2008"]`}, false},
+	{"12/22/78", []string{`american ["12" "22" "78"]`}, false},
+	{"1/1", []string{`americanshort ["1" "1"]`}, false},
+	{"78-Dec-22", []string{`pgtextreverse ["78" "Dec" "22"]`}, false},
+	{"Dec-22-78", []string{`pgtextshort ["Dec" "22" "78"]`}, false},
+	{"22-Dec-78", []string{`datefull ["22" "Dec" "78"]`}, false},
+	{"22 December 1978", []string{`datefull ["22" "December" "1978"]`}, false},
+	{"December 22, 1978", []string{`datetextual ["December" "22" "1978"]`}, false},
+	{"22.12.78", []string{`pointeddate2 ["22" "12" "78"]`}, false},
+	{"1978-12", []string{`gnudateshorter ["1978" "12"]`}, false},
+	{"19781222", []string{`datenocolon ["1978" "12" "22"]`}, false},
+	{"1978 12 22", []string{`year4 ["1978"]`}, true},
+	{"t18:11:31", []string{`timelong24 ["18" "11" "31"]`}, false},
+	{"18:11", []string{`timeshort24 ["18" "11"]`}, false},
+	{"6am", []string{`timeTiny12 ["6" "am"]`}, false},
+	{"6 p.m.", nil, true},
+	{"6 pm +3 weeks", []string{`timeTiny12 ["6" "pm"]`, `relative ["+" "3" "weeks"]`}, false},
+	{"tuesday 14:00", []string{`daytext ["tuesday"]`, `timeshort24 ["14" "00"]`}, false},
+	{"last monday of next month", []string{`relativetext ["last" "monday"]`}, true},
+	{"first day of next year", []string{`firstdayof | lastdayof ["first"]`, `relativetext ["next" "year"]`}, false},
+	{"+1 week 2 days 4 hours 2 seconds", []string{`relative ["+" "1" "week"]`, `relative ["" "2" "days"]`, `relative ["" "4" "hours"]`, `relative ["" "2" "seconds"]`}, false},
+	{"3 days ago", []string{`relative ["" "3" "days"]`, `ago []`}, false},
+	{"back of 7pm", nil, true},
+	{"front of 19", nil, true},
+	{"xii", nil, true},
+	{"iv 2008", []string{`datenoday ["iv" "2008"]`}, false},
+	{"24:00", []string{`timeshort24 ["24" "00"]`}, false},
+	{"25:00", nil, true},
+	{"12:60", nil, true},
+	{"Jan 32", []string{`monthfull | monthabbr ["Jan"]`}, true},
+	{"GMT+05:30", []string{`tzcorrection ["GMT+05:30" "+" "05" "30"]`}, false},
+	{"12:00 EST", []string{`timeshort24 ["12" "00"]`}, true},
+	{"???", nil, true},
+	{"1stjanuary", []string{`datenoyearrev ["1" "january"]`}, false},
+	{"2ndfeb 2008", []string{`datefull ["2" "feb" "2008"]`}, false},
+	{"5thursday", []string{`relative ["" "5" "thursday"]`}, false},
+}
+
+func TestGrammar(t *testing.T) {
+	for _, tt := range grammarTests {
+		t.Run(tt.in, func(t *testing.T) {
+			var steps []string
+
+			_, err := parseFormats(tt.in, traced(allFormats, &steps))
+
+			if !reflect.DeepEqual(steps, tt.steps) {
+				t.Errorf("Steps should have been %v, but they were %v", tt.steps, steps)
+			}
+			if (err != nil) != tt.fails {
+				t.Errorf("Error should have been %v, but it was %v", tt.fails, err)
+			}
+		})
+	}
+}
+
+// baselineTests are what Parse read each of these as, relative to now, before the grammar replaced the regex
+// cascade: the result, or whether it failed.
+var baselineTests = []struct {
+	in    string
+	out   int64
+	fails bool
+}{
+	{"", 1436101200, false},
+	{" ", 1436101200, false},
+	{"\n", 0, true},
+	{"now ", 1436101200, false},
+	{"  now", 1436101200, false},
+	{"now\n", 1436101200, false},
+	{"now, tomorrow", 1436187600, false},
+	{"nowhere", 0, true},
+	{"20081", 0, true},
+	{"@-86400", -86400, false},
+	{"@", 0, true},
+	{"@1569600000.123", 0, true},
+	{"@1569600000123ms", 0, true},
+	{"+500 ms", 0, true},
+	{"+500 msecs", 0, true},
+	{"+500 mss", 0, true},
+	{"10 µs ago", 0, true},
+	{"next nanosecond", 0, true},
+	{"@1569600000000ms", 0, true},
+	{"@1569600000000000µs", 0, true},
+	{"@1569600000000msec", 0, true},
+	{"excel 45292.75", 0, true},
+	{"excel1904 43830", 0, true},
+	{"excel1905 43830", 0, true},
+	{"jdn 2460311", 0, true},
+	{"1569600000123", 0, true},
+	{"1569600000123.5", 127172775900, false},
+	{"15696000001234567890", 186815462400, false},
+	{"2008-08-07T18:11:31.0Z", 1218132691, false},
+	{"2008-08-07 18:11:31 +02:00", 1218125491, false},
+	{"2008-W28-3", 1215561600, false},
+	{"2008-W28", 0, true},
+	{"2008.197", 1216080000, false},
+	{"31.12.2008", 1230681600, false},
+	{"12/22/78", 283132800, false},
+	{"1/1", 1420070400, false},
+	{"78-Dec-22", 283132800, false},
+	{"Dec-22-78", 283132800, false},
+	{"22-Dec-78", 283132800, false},
+	{"22 December 1978", 283132800, false},
+	{"December 22, 1978", 283132800, false},
+	{"22.12.78", 283132800, false},
+	{"1978-12", 281318400, false},
+	{"19781222", 283132800, false},
+	{"1978 12 22", 0, true},
+	{"t18:11:31", 1436119891, false},
+	{"18:11", 1436119860, false},
+	{"6am", 1436076000, false},
+	{"6 p.m.", 0, true},
+	{"6 pm +3 weeks", 1437933600, false},
+	{"tuesday 14:00", 1436277600, false},
+	{"last monday of next month", 0, true},
+	{"first day of next year", 1467378000, false},
+	{"+1 week 2 days 4 hours 2 seconds", 1436893202, false},
+	{"3 days ago", 1435842000, false},
+	{"back of 7pm", 0, true},
+	{"front of 19", 0, true},
+	{"xii", 0, true},
+	{"iv 2008", 1207008000, false},
+	{"24:00", 1436140800, false},
+	{"25:00", 0, true},
+	{"12:60", 0, true},
+	{"Jan 32", 0, true},
+	{"GMT+05:30", 1436081400, false},
+	{"12:00 EST", 0, true},
+	{"???", 0, true},
+	{"snooze", 0, true},
+	{"tomorrowland", 0, true},
+	{"todays", 0, true},
+	{"noonish", 0, true},
+	{"yesterdays news", 0, true},
+	{"yesterday noon", 1436011200, false},
+	{"now", 1436101200, false},
+	{"midnight", 1436054400, false},
+	{"tomorrow", 1436187600, false},
+	{"@1569600000", 1569600000, false},
+	{"last day of October", 1446249600, false},
+	{"01:59:59.040", 1436061599, false},
+	{"01:59:59.040pm", 1436104799, false},
+	{"16:59:59.040", 1436115599, false},
+	{"01:59:59 pm", 1436104799, false},
+	{"01:59:59am", 1436061599, false},
+	{"01.59.59pm", 1436104799, false},
+	{"01:59 pm", 1436104740, false},
+	{"01:59pm", 1436104740, false},
+	{"01.59pm", 1436104740, false},
+	{"01 pm", 1436101200, false},
+	{"01am", 1436058000, false},
+	{"tomorrow 01am", 1436144400, false},
+	{"1am 2pm", 0, true},
+	{"2008-10-31T15:07:38.6875000-05:00", 1225483658, false},
+	{"2008-10-31T15:07:38.034567890GMT-05:00", 1225483658, false},
+	{"2008-10-31T15:07:38.034567890Z", 1225465658, false},
+	{"2008-10-31T15:07:38", 1225465658, false},
+	{"2008:10:31 15:07:38", 1225465658, false},
+	{"20081031T15:07:38", 1225465658, false},
+	{"20081031T150738", 1225465658, false},
+	{"20081031t150738", 1225465658, false},
+	{"31/Oct/2008:15:07:38 -0500", 1225483658, false},
+	{"T13:59:59.040", 1436104799, false},
+	{"July 5th, 2015", 1436054400, false},
+	{"5.07.2015", 1436054400, false},
+	{"5.07.15", 1436054400, false},
+	{"13:59:59", 1436104799, false},
+	{"20150705", 1436054400, false},
+	{"2015186", 1436054400, false},
+	{"13:59", 1436104740, false},
+	{"135959", 1436104799, false},
+	{"2015/07/05", 1436054400, false},
+	{"2015/07/05/", 1436054400, false},
+	{"2015/7/5", 1436054400, false},
+	{"7/5/2015", 1436054400, false},
+	{"7/5", 1436054400, false},
+	{"93-3-19", 732499200, false},
+	{"1993-03-19", 732499200, false},
+	{"t1359", 1436104740, false},
+	{"2019-01", 1546300800, false},
+	{"2015-jul-05", 1436054400, false},
+	{"05-jul-2015", 1436054400, false},
+	{"05-july-2015", 1436054400, false},
+	{"jan-2019", 1546300800, false},
+	{"2019-jan", 1546300800, false},
+	{"jul-05-2015", 1436054400, false},
+	{"January 1st", 1420070400, false},
+	{"1st January", 1420070400, false},
+	{"2019-W01-1", 1546214400, false},
+	{"2019-W02-7", 1547337600, false},
+	{"2018-W02-7", 1515888000, false},
+	{"2016-W02-7", 1452988800, false},
+	{"2015-W02-7", 1420934400, false},
+	{"2014-W02-7", 1389484800, false},
+	{"next year", 1467723600, false},
+	{"next day", 1436187600, false},
+	{"next hour", 1436104800, false},
+	{"next minute", 1436101260, false},
+	{"next second", 1436101201, false},
+	{"next week", 1436187600, false},
+	{"last week", 1434978000, false},
+	{"last month", 1433509200, false},
+	{"next month", 1438779600, false},
+	{"previous month", 1433509200, false},
+	{"monday this week GMT-05:00", 1435554000, false},
+	{"-1 day +1 month", 1438693200, false},
+	{"-1 day 0 month", 1436014800, false},
+	{"1359", 1436104740, false},
+	{"1993", 741877200, false},
+	{"November 15 2019 front of 7pm", 1573843500, false},
+	{"January 00 2019 front of 24am", 1546299900, false},
+}
+
+// baselineChanges are the baseline tests that later changes read differently on purpose
+var baselineChanges = map[string]string{
+	"@1569600000.123":      "fractional timestamps",
+	"@1569600000123ms":     "millisecond timestamps",
+	"@1569600000000ms":     "millisecond timestamps",
+	"@1569600000000000µs":  "microsecond timestamps",
+	"+500 ms":              "millisecond shifts",
+	"+500 msecs":           "millisecond shifts",
+	"10 µs ago":            "microsecond shifts",
+	"next nanosecond":      "nanosecond shifts",
+	"excel 45292.75":       "named epochs",
+	"excel1904 43830":      "named epochs",
+	"jdn 2460311":          "named epochs",
+	"1569600000123.5":      "long runs of digits are timestamps, read only with BareTimestamps",
+	"15696000001234567890": "long runs of digits are timestamps, read only with BareTimestamps",
+	"2008-W28":             "ISO weeks without a day are their Monday",
+}
+
+// TestGrammarMatchesBaseline checks that the grammar reads strings as the regex cascade did, but for the
+// changes made since on purpose.
+func TestGrammarMatchesBaseline(t *testing.T) {
+	for _, tt := range baselineTests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in, now.Unix())
+			same := (err != nil) == tt.fails && (err != nil || got == tt.out)
+
+			if change, ok := baselineChanges[tt.in]; ok {
+				if same {
+					t.Errorf("Should have been read differently from the baseline, for %v", change)
+				}
+				return
+			}
+
+			if !same {
+				t.Errorf("Result should have been %v (failing: %v), but it was %v (%v)", tt.out, tt.fails, got, err)
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, test := range parseTests {
+			parseFormats(test.in, allFormats)
+		}
+	}
+}
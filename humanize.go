@@ -0,0 +1,275 @@
+package strtotime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RelativeOptions tunes the phrases FormatRelative produces.
+type RelativeOptions struct {
+	// Granularity is the finest unit of time a phrase mentions, and so how close to the original
+	// time parsing the phrase back gets. The zero value, Secondly, is exact to the second.
+	Granularity Frequency
+
+	// Thresholds sets, per unit, the amount at which a phrase moves on to the next coarser unit.
+	// Units left out default to 60 seconds, 60 minutes, 24 hours, 7 days, 4 weeks and 12 months,
+	// so that 36 hours read "1 day 12 hours"; with Thresholds[Hourly] = 48 they read "36 hours".
+	Thresholds map[Frequency]int
+
+	// Calendar allows phrases relative to the calendar rather than to the exact time, such as
+	// "yesterday at 4:05 PM", "next Friday" or "last March", whenever one applies.
+	Calendar bool
+}
+
+var defaultThresholds = map[Frequency]int{
+	Secondly: 60,
+	Minutely: 60,
+	Hourly:   24,
+	Daily:    7,
+	Weekly:   4,
+	Monthly:  12,
+}
+
+// unitNames holds the English name of the unit of each Frequency
+var unitNames = []string{"second", "minute", "hour", "day", "week", "month", "year"}
+
+// approximately returns the average length of one unit of the frequency
+func (f Frequency) approximately() time.Duration {
+	switch f {
+	case Monthly:
+		return 2629746 * time.Second
+	case Yearly:
+		return 31556952 * time.Second
+	}
+
+	return f.duration()
+}
+
+// FormatRelative describes t relative to ref in English, such as "3 days ago" or "in 2 hours". It is the inverse of Parse:
+// parsing the phrase relative to ref gives back t, to within one unit of opts.Granularity. Like Parse, it works in UTC.
+func FormatRelative(t, ref time.Time, opts RelativeOptions) string {
+	t, ref = t.UTC(), ref.UTC().Truncate(time.Second)
+
+	if opts.Calendar {
+		if phrase, ok := calendarPhrase(t, ref, opts.Granularity); ok && roundTrips(phrase, t, ref, opts.Granularity) {
+			return phrase
+		}
+	}
+
+	return durationPhrase(t, ref, opts)
+}
+
+// durationPhrase describes t as an amount of time before or after ref, such as "1 day 12 hours ago"
+func durationPhrase(t, ref time.Time, opts RelativeOptions) string {
+	sign := 1
+
+	if t.Before(ref) {
+		sign = -1
+	}
+
+	diff := t.Sub(ref)
+	if diff < 0 {
+		diff = -diff
+	}
+
+	// move on to coarser units for as long as the amount reaches their threshold
+	lead := opts.Granularity
+	for lead < Yearly {
+		threshold, ok := opts.Thresholds[lead]
+		if !ok {
+			threshold = defaultThresholds[lead]
+		}
+
+		if diff < time.Duration(threshold)*lead.approximately() {
+			break
+		}
+
+		lead++
+	}
+
+	overshoots := func(p Period) bool {
+		shifted := p.AddTo(ref)
+		return (sign > 0 && shifted.After(t)) || (sign < 0 && shifted.Before(t))
+	}
+
+	var p Period
+	var parts []string
+
+	for unit := lead; unit >= opts.Granularity && unit <= lead; unit-- {
+		// weeks only ever lead, "1 month 17 days" reads better than "1 month 2 weeks 3 days"
+		if unit == Weekly && lead != Weekly && opts.Granularity != Weekly {
+			continue
+		}
+
+		remaining := t.Sub(p.AddTo(ref))
+		if remaining < 0 {
+			remaining = -remaining
+		}
+
+		n := int(remaining / unit.approximately())
+
+		for n > 0 && overshoots(p.add(unit, sign*n)) {
+			n--
+		}
+
+		for !overshoots(p.add(unit, sign*(n+1))) {
+			n++
+		}
+
+		// round the finest unit to the nearest
+		if unit == opts.Granularity {
+			below, above := p.add(unit, sign*n).AddTo(ref), p.add(unit, sign*(n+1)).AddTo(ref)
+			if absDuration(above.Sub(t)) <= absDuration(t.Sub(below)) {
+				n++
+			}
+		}
+
+		p = p.add(unit, sign*n)
+
+		if n == 1 {
+			parts = append(parts, "1 "+unitNames[unit])
+		} else if n > 1 {
+			parts = append(parts, fmt.Sprintf("%v %vs", n, unitNames[unit]))
+		}
+	}
+
+	switch {
+	case len(parts) == 0:
+		return "now"
+	case sign < 0:
+		return strings.Join(parts, " ") + " ago"
+	}
+
+	return "in " + strings.Join(parts, " ")
+}
+
+// calendarPhrase describes t by its place in the calendar relative to ref, such as "tomorrow at 9 AM",
+// "last Friday" or "next March". It reports false when no such phrase applies.
+func calendarPhrase(t, ref time.Time, granularity Frequency) (string, bool) {
+	ty, tm, td := t.Date()
+	ry, rm, rd := ref.Date()
+
+	days := int(time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC).Sub(time.Date(ry, rm, rd, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+	months := (ty-ry)*12 + int(tm) - int(rm)
+
+	switch granularity {
+	case Yearly:
+		return relativeWord(ty-ry) + " year", ty-ry >= -1 && ty-ry <= 1
+	case Monthly:
+		switch {
+		case months == 0:
+			return "this month", true
+		case months > 0 && months < 12:
+			return "next " + tm.String(), true
+		case months < 0 && months > -12:
+			return "last " + tm.String(), true
+		}
+		return "", false
+	case Weekly:
+		weeks := (days + (int(ref.Weekday())+6)%7) / 7
+		if days+(int(ref.Weekday())+6)%7 < 0 {
+			weeks--
+		}
+		return relativeWord(weeks) + " week", weeks >= -1 && weeks <= 1
+	}
+
+	var phrase string
+
+	switch {
+	case days == 0:
+		phrase = "today"
+	case days == -1:
+		phrase = "yesterday"
+	case days == 1:
+		phrase = "tomorrow"
+	case days > 1 && days < 7:
+		phrase = "next " + t.Weekday().String()
+	case days < -1 && days > -7:
+		phrase = "last " + t.Weekday().String()
+	default:
+		return "", false
+	}
+
+	h, i, s := t.Clock()
+	meridian := "AM"
+
+	if h >= 12 {
+		meridian = "PM"
+	}
+
+	h = (h+11)%12 + 1
+
+	switch granularity {
+	case Hourly:
+		phrase += fmt.Sprintf(" at %v %v", h, meridian)
+	case Minutely:
+		phrase += fmt.Sprintf(" at %v:%02d %v", h, i, meridian)
+	case Secondly:
+		phrase += fmt.Sprintf(" at %v:%02d:%02d %v", h, i, s, meridian)
+	}
+
+	return phrase, true
+}
+
+// relativeWord returns "last", "this" or "next" for -1, 0 and 1
+func relativeWord(n int) string {
+	switch {
+	case n < 0:
+		return "last"
+	case n > 0:
+		return "next"
+	}
+
+	return "this"
+}
+
+// roundTrips reports whether parsing phrase relative to ref gives back t, to within one unit of granularity.
+func roundTrips(phrase string, t, ref time.Time, granularity Frequency) bool {
+	u, err := Parse(phrase, ref.Unix())
+
+	if err != nil {
+		return false
+	}
+
+	unit := granularity.duration()
+
+	switch granularity {
+	case Monthly:
+		unit = 31 * 24 * time.Hour
+	case Yearly:
+		unit = 366 * 24 * time.Hour
+	}
+
+	return absDuration(time.Unix(u, 0).Sub(t)) < unit
+}
+
+// add returns the period with n more of the unit of the given frequency
+func (p Period) add(unit Frequency, n int) Period {
+	switch unit {
+	case Secondly:
+		p.Seconds += n
+	case Minutely:
+		p.Minutes += n
+	case Hourly:
+		p.Hours += n
+	case Daily:
+		p.Days += n
+	case Weekly:
+		p.Days += 7 * n
+	case Monthly:
+		p.Months += n
+	case Yearly:
+		p.Years += n
+	}
+
+	return p
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
@@ -0,0 +1,74 @@
+package strtotime
+
+import (
+	"testing"
+	"time"
+)
+
+var formatRelativeTests = []struct {
+	t    time.Time
+	opts RelativeOptions
+	out  string
+}{
+	{now, RelativeOptions{}, "now"},
+	{now.Add(-3 * 24 * time.Hour), RelativeOptions{}, "3 days ago"},
+	{now.Add(2 * time.Hour), RelativeOptions{}, "in 2 hours"},
+	{now.Add(90 * time.Second), RelativeOptions{}, "in 1 minute 30 seconds"},
+	{now.Add(90 * time.Second), RelativeOptions{Granularity: Minutely}, "in 2 minutes"},
+	{now.Add(-(3*24*time.Hour + 5*time.Hour + 2*time.Minute)), RelativeOptions{Granularity: Hourly}, "3 days 5 hours ago"},
+	{now.Add(-(3*24*time.Hour + 5*time.Hour + 2*time.Minute)), RelativeOptions{Granularity: Daily}, "3 days ago"},
+	{now.Add(36 * time.Hour), RelativeOptions{Granularity: Hourly}, "in 1 day 12 hours"},
+	{now.Add(36 * time.Hour), RelativeOptions{Granularity: Hourly, Thresholds: map[Frequency]int{Hourly: 48}}, "in 36 hours"},
+	{now.AddDate(0, 0, 16), RelativeOptions{Granularity: Daily}, "in 2 weeks 2 days"},
+	{now.AddDate(0, 1, 17), RelativeOptions{Granularity: Daily}, "in 1 month 17 days"},
+	{now.AddDate(-2, -3, 0), RelativeOptions{Granularity: Monthly}, "2 years 3 months ago"},
+	{date(2015, 7, 4, 16, 5), RelativeOptions{Granularity: Minutely, Calendar: true}, "yesterday at 4:05 PM"},
+	{date(2015, 7, 6, 9, 0), RelativeOptions{Granularity: Hourly, Calendar: true}, "tomorrow at 9 AM"},
+	{date(2015, 7, 10, 0, 0), RelativeOptions{Granularity: Daily, Calendar: true}, "next Friday"},
+	{date(2015, 7, 1, 18, 0), RelativeOptions{Granularity: Daily, Calendar: true}, "last Wednesday"},
+	{date(2015, 3, 12, 0, 0), RelativeOptions{Granularity: Monthly, Calendar: true}, "last March"},
+	{date(2016, 2, 2, 0, 0), RelativeOptions{Granularity: Monthly, Calendar: true}, "next February"},
+	{date(2015, 9, 20, 13, 0), RelativeOptions{Granularity: Daily, Calendar: true}, "in 2 months 15 days"},
+}
+
+func TestFormatRelative(t *testing.T) {
+	for _, tt := range formatRelativeTests {
+		t.Run(tt.out, func(t *testing.T) {
+			out := FormatRelative(tt.t, now, tt.opts)
+			if out != tt.out {
+				t.Errorf("FormatRelative should have returned %q, but it returned %q", tt.out, out)
+			}
+		})
+	}
+}
+
+func TestFormatRelativeRoundTrip(t *testing.T) {
+	units := map[Frequency]time.Duration{
+		Secondly: time.Second,
+		Minutely: time.Minute,
+		Hourly:   time.Hour,
+		Daily:    24 * time.Hour,
+		Weekly:   7 * 24 * time.Hour,
+		Monthly:  31 * 24 * time.Hour,
+		Yearly:   366 * 24 * time.Hour,
+	}
+
+	for granularity := Secondly; granularity <= Yearly; granularity++ {
+		for _, calendar := range []bool{false, true} {
+			opts := RelativeOptions{Granularity: granularity, Calendar: calendar}
+
+			for offset := 37 * time.Second; offset < 5*366*24*time.Hour; offset = offset*3/2 + 7*time.Second {
+				for _, tm := range []time.Time{now.Add(offset), now.Add(-offset)} {
+					phrase := FormatRelative(tm, now, opts)
+					u, err := Parse(phrase, now.Unix())
+					if err != nil {
+						t.Fatalf("%q could not be parsed: %v", phrase, err)
+					}
+					if d := absDuration(time.Unix(u, 0).Sub(tm)); d >= units[granularity] {
+						t.Errorf("%q should have parsed to within %v of %v, but it parsed to %v", phrase, units[granularity], tm, time.Unix(u, 0).UTC())
+					}
+				}
+			}
+		}
+	}
+}
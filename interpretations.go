@@ -0,0 +1,204 @@
+package strtotime
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Interpretation is one way of reading a string, returned by ParseAll.
+type Interpretation struct {
+	Time time.Time
+
+	// Formats are the names of the formats that read the string, in order
+	Formats []string
+
+	// Score ranks the interpretation, from 1 for the one Parse picks down towards 0. Each format
+	// that was not the first to match where it did divides it by how far down it was.
+	Score float64
+}
+
+// maxPaths caps how many ways of matching the whole string ParseAll tries, as strings made of
+// many ambiguous parts, such as "1230 1230 1230", can be read in exponentially many ways
+const maxPaths = 256
+
+// ParseAll returns every way of reading s, relative to the unix timestamp, as Parse would if other formats
+// had come first: "1230" is half past noon, or the year 1230. It also reads numeric dates day first and year
+// first, so that "03/04/05" is March 4th, April 3rd, or April 5th 2003, and a number on its own as an hour or
+// a day of the month, although Parse reads neither. Interpretations are sorted from the highest score, so the
+// first is the one Parse returns, and those that resolve to the same time are only listed once. ParseAll
+// returns the error Parse does when there is no way to read s.
+func ParseAll(s string, relativeTo int64, opts ...Option) ([]Interpretation, error) {
+	o := newOptions(opts)
+	original := s
+	s = o.locale.translate(s)
+	in := lex(s)
+
+	formats := o.formats()
+	formats = append(formats[:len(formats):len(formats)], alternativeFormats...)
+
+	var all []Interpretation
+	paths := 0
+
+	var walk func(pos int, r *result, names []string, score float64)
+	walk = func(pos int, r *result, names []string, score float64) {
+		t, _ := in.at(pos)
+
+		if t == nil {
+			paths++
+			if o.check(r) != nil {
+				return
+			}
+
+			if t, err := r.clone().resolve(relativeTo); err == nil {
+				all = append(all, Interpretation{Time: t, Formats: names, Score: score})
+			}
+			return
+		}
+
+		rank := 0
+
+		for i, format := range formats {
+			if paths >= maxPaths {
+				return
+			}
+
+			if format.rule.starts&t.kind.set() == 0 {
+				continue
+			}
+
+			match := format.rule.parse(in, pos)
+
+			if len(match) <= 0 {
+				continue
+			}
+
+			// alternatives rank below the format Parse picks, even where there is none
+			if i >= len(formats)-len(alternativeFormats) && rank == 0 {
+				rank++
+			}
+
+			rank++
+			next := r.clone()
+
+			// the formats that contradict those before them lead nowhere
+			if format.callback(next, match[1:]...) != nil {
+				continue
+			}
+
+			walk(skipSpace(s, pos+len(match[0])), next, append(names[:len(names):len(names)], format.name), score/float64(rank))
+		}
+	}
+
+	r := &result{}
+	o.configure(r)
+
+	walk(skipSpace(s, 0), r, nil, 1)
+
+	if len(all) == 0 {
+		r, err := parseFormats(s, o.formats())
+		if err == nil {
+			err = o.check(r)
+		}
+		if err == nil {
+			_, err = r.resolve(relativeTo)
+		}
+		return nil, outOfRange(untranslated(err, original, s), original)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Score > all[j].Score
+	})
+
+	var unique []Interpretation
+
+	for _, i := range all {
+		if !hasTime(unique, i.Time) {
+			unique = append(unique, i)
+		}
+	}
+
+	return unique, nil
+}
+
+// alternativeFormats are the readings that ParseAll offers besides those of Parse's formats
+var alternativeFormats = alternatives()
+
+// alternatives returns formats that Parse leaves out, as they would shadow those it has: numeric dates read day
+// first or year first, rather than as American dates, and numbers on their own read as an hour or a day
+func alternatives() []format {
+	american := formatNamed("american")
+	dayOfMonth := formatNamed("dayofmonth")
+
+	dayFirst := format{
+		rule: seq(dayNumber, char("/"), month, char("/"), year),
+		name: "dayfirst",
+		callback: func(r *result, inputs ...string) error {
+			return american.callback(r, inputs[1], inputs[0], inputs[2])
+		},
+	}
+
+	yearFirst := format{
+		rule: seq(year, char("/"), month, char("/"), dayNumber),
+		name: "yearfirst",
+		callback: func(r *result, inputs ...string) error {
+			return american.callback(r, inputs[1], inputs[2], inputs[0])
+		},
+	}
+
+	hour := format{
+		rule: alone(hour24),
+		name: "hour",
+		callback: func(r *result, inputs ...string) error {
+			r.given |= givenHour | givenMinute
+
+			hour, err := strconv.Atoi(inputs[0])
+			if err != nil {
+				return err
+			}
+
+			return r.time(hour, 0, 0, 0)
+		},
+	}
+
+	day := format{
+		rule:     alone(dayNumber),
+		name:     "day",
+		callback: dayOfMonth.callback,
+	}
+
+	return []format{dayFirst, yearFirst, hour, day}
+}
+
+// formatNamed returns the format of Parse's with the given name
+func formatNamed(name string) format {
+	for _, f := range allFormats {
+		if f.name == name {
+			return f
+		}
+	}
+
+	panic("strtotime: no format is named " + name)
+}
+
+// stepNames returns the names of the formats of the steps
+func stepNames(steps []step) []string {
+	var names []string
+
+	for _, step := range steps {
+		names = append(names, step.format.name)
+	}
+
+	return names
+}
+
+// hasTime reports whether one of the interpretations resolves to t
+func hasTime(all []Interpretation, t time.Time) bool {
+	for _, i := range all {
+		if i.Time.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
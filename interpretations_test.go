@@ -0,0 +1,85 @@
+package strtotime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var parseAllTests = []struct {
+	in  string
+	out []Interpretation
+}{
+	{"1230", []Interpretation{
+		{Time: time.Date(2015, 7, 5, 12, 30, 0, 0, time.UTC), Formats: []string{"gnunocolon"}, Score: 1},
+		{Time: time.Date(1230, 7, 5, 13, 0, 0, 0, time.UTC), Formats: []string{"year4"}, Score: 0.5},
+	}},
+	{"12.10.08", []Interpretation{
+		{Time: time.Date(2008, 10, 12, 0, 0, 0, 0, time.UTC), Formats: []string{"pointeddate2"}, Score: 1},
+		{Time: time.Date(2015, 7, 5, 12, 10, 8, 0, time.UTC), Formats: []string{"timelong24"}, Score: 0.5},
+	}},
+	{"tomorrow 2008", []Interpretation{
+		{Time: time.Date(2015, 7, 6, 20, 8, 0, 0, time.UTC), Formats: []string{"tomorrow", "gnunocolon"}, Score: 1},
+		{Time: time.Date(2008, 7, 6, 13, 0, 0, 0, time.UTC), Formats: []string{"tomorrow", "year4"}, Score: 0.5},
+	}},
+	{"2008 2008", []Interpretation{
+		{Time: time.Date(2008, 7, 5, 20, 8, 0, 0, time.UTC), Formats: []string{"gnunocolon", "year4"}, Score: 0.5},
+		{Time: time.Date(2008, 7, 5, 13, 0, 0, 0, time.UTC), Formats: []string{"year4", "year4"}, Score: 0.25},
+	}},
+	{"03/04/05", []Interpretation{
+		{Time: time.Date(2005, 3, 4, 0, 0, 0, 0, time.UTC), Formats: []string{"american"}, Score: 1},
+		{Time: time.Date(2005, 4, 3, 0, 0, 0, 0, time.UTC), Formats: []string{"dayfirst"}, Score: 1.0 / 3},
+		{Time: time.Date(2003, 4, 5, 0, 0, 0, 0, time.UTC), Formats: []string{"yearfirst"}, Score: 0.25},
+	}},
+	{"12", []Interpretation{
+		{Time: time.Date(2015, 7, 5, 12, 0, 0, 0, time.UTC), Formats: []string{"hour"}, Score: 0.5},
+		{Time: time.Date(2015, 7, 12, 0, 0, 0, 0, time.UTC), Formats: []string{"day"}, Score: 1.0 / 3},
+	}},
+	{"", []Interpretation{
+		{Time: now, Score: 1},
+	}},
+}
+
+func TestParseAll(t *testing.T) {
+	for _, test := range parseAllTests {
+		t.Run(test.in, func(t *testing.T) {
+			out, err := ParseAll(test.in, now.Unix())
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(out, test.out) {
+				t.Errorf("Interpretations should have been %+v, but they were %+v", test.out, out)
+			}
+		})
+	}
+}
+
+// TestParseAllFirst checks that the first interpretation is the one Parse returns
+func TestParseAllFirst(t *testing.T) {
+	for _, test := range parseTests {
+		want, err := Parse(test.in, now.Unix())
+		all, allErr := ParseAll(test.in, now.Unix())
+
+		if err != nil {
+			if allErr == nil && all[0].Score == 1 {
+				t.Errorf("ParseAll(%q) read it as %v, which Parse failed to", test.in, all[0].Time)
+			}
+			continue
+		}
+
+		if allErr != nil || all[0].Score != 1 || all[0].Time.Unix() != want {
+			t.Errorf("ParseAll(%q) = %+v (%v), want %v first", test.in, all, allErr, time.Unix(want, 0).UTC())
+		}
+	}
+}
+
+func TestParseAllErrors(t *testing.T) {
+	for _, in := range []string{"99", "nowhere", "next", "03/04/2005/06"} {
+		_, want := Parse(in, now.Unix())
+
+		if _, err := ParseAll(in, now.Unix()); err == nil || (want != nil && err.Error() != want.Error()) {
+			t.Errorf("ParseAll(%q) should have failed as Parse does, with %v, but got %v", in, want, err)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package strtotime
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const reISODuration = `^([+-])?P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`
+
+// Interval is a span of time, such as one given as an ISO 8601 time interval.
+// Period is only set when the interval was expressed with a duration, and when that duration
+// came without a start or an end, Start and End are both the zero Time.
+type Interval struct {
+	Start  time.Time
+	End    time.Time
+	Period Period
+}
+
+// RepeatingInterval is an ISO 8601 repeating interval, such as "R5/2008-03-01T13:00:00Z/P1Y2M10DT2H30M".
+// Repetitions is the number of intervals, or -1 when they repeat forever.
+type RepeatingInterval struct {
+	Interval
+	Repetitions int
+
+	// backwards is set for intervals given as a duration and an end
+	backwards bool
+}
+
+// ParseISODuration takes an ISO 8601 duration - such as "P3Y6M4DT12H30M5S", "PT15M" or "P2W" - and returns
+// it as a Period, or an error if the input is not a duration. Only the smallest unit of the time part
+// may have a fraction, and weeks are counted as days.
+func ParseISODuration(s string) (Period, error) {
+	match := regexp.MustCompile(reISODuration).FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+
+	if match == nil || strings.HasSuffix(match[0], "P") || strings.HasSuffix(match[0], "T") {
+		return Period{}, fmt.Errorf(`strtotime: "%v" is not an ISO 8601 duration`, s)
+	}
+
+	// the first number too large to count with
+	var overflow error
+
+	atoi := func(s string) int {
+		n, err := strconv.Atoi(s)
+		if s != "" && err != nil && overflow == nil {
+			overflow = &RangeError{Value: s}
+		}
+		return n
+	}
+
+	p := Period{
+		Years:  atoi(match[2]),
+		Months: atoi(match[3]),
+		Days:   atoi(match[5]),
+	}
+
+	if weeks := atoi(match[4]); !addShift(&p.Days, weeks, 7) && overflow == nil {
+		overflow = &RangeError{Value: match[4]}
+	}
+
+	units := []struct {
+		value string
+		field *int
+		unit  time.Duration
+	}{
+		{match[6], &p.Hours, time.Hour},
+		{match[7], &p.Minutes, time.Minute},
+		{match[8], &p.Seconds, time.Second},
+	}
+
+	for i, u := range units {
+		if len(u.value) == 0 {
+			continue
+		}
+
+		whole, frac := u.value, ""
+
+		if j := strings.IndexAny(u.value, ".,"); j >= 0 {
+			whole, frac = u.value[:j], u.value[j+1:]
+		}
+
+		*u.field = atoi(whole)
+
+		if overflow != nil {
+			return Period{}, overflow
+		}
+
+		if len(frac) == 0 {
+			continue
+		}
+
+		for _, smaller := range units[i+1:] {
+			if len(smaller.value) > 0 {
+				return Period{}, fmt.Errorf(`strtotime: Only the smallest unit of "%v" may have a fraction`, s)
+			}
+		}
+
+		f, err := strconv.ParseFloat("0."+frac, 64)
+		if err != nil {
+			return Period{}, err
+		}
+
+		// carry the fraction over to the smaller units
+		rest := time.Duration(math.Round(f * float64(u.unit)))
+		p.Minutes += int(rest / time.Minute)
+		rest %= time.Minute
+		p.Seconds += int(rest / time.Second)
+		p.Nanoseconds = int(rest % time.Second)
+	}
+
+	if overflow != nil {
+		return Period{}, overflow
+	}
+
+	if match[1] == "-" {
+		p = p.scale(-1)
+	}
+
+	return p, nil
+}
+
+// String returns the period as an ISO 8601 duration, such as "P1Y2M3DT4H". Periods with both positive
+// and negative units, which ISO 8601 cannot express, get a sign on each negative unit.
+func (p Period) String() string {
+	sign := ""
+
+	if p.Years <= 0 && p.Months <= 0 && p.Days <= 0 && p.Hours <= 0 && p.Minutes <= 0 && p.Seconds <= 0 && p.Nanoseconds <= 0 && p != (Period{}) {
+		sign = "-"
+		p = p.scale(-1)
+	}
+
+	var b strings.Builder
+
+	b.WriteString(sign + "P")
+
+	for _, u := range []struct {
+		n      int
+		symbol string
+	}{{p.Years, "Y"}, {p.Months, "M"}, {p.Days, "D"}} {
+		if u.n != 0 {
+			b.WriteString(strconv.Itoa(u.n) + u.symbol)
+		}
+	}
+
+	seconds := ""
+
+	if p.Seconds != 0 || p.Nanoseconds != 0 {
+		ns := int64(p.Seconds)*int64(time.Second) + int64(p.Nanoseconds)
+		seconds = strconv.FormatFloat(float64(ns)/float64(time.Second), 'f', -1, 64) + "S"
+	}
+
+	if p.Hours != 0 || p.Minutes != 0 || len(seconds) > 0 {
+		b.WriteString("T")
+
+		if p.Hours != 0 {
+			b.WriteString(strconv.Itoa(p.Hours) + "H")
+		}
+
+		if p.Minutes != 0 {
+			b.WriteString(strconv.Itoa(p.Minutes) + "M")
+		}
+
+		b.WriteString(seconds)
+	}
+
+	if b.Len() == len(sign)+1 {
+		return "PT0S"
+	}
+
+	return b.String()
+}
+
+// scale returns the period with every unit multiplied by n
+func (p Period) scale(n int) Period {
+	return Period{
+		Years:       p.Years * n,
+		Months:      p.Months * n,
+		Days:        p.Days * n,
+		Hours:       p.Hours * n,
+		Minutes:     p.Minutes * n,
+		Seconds:     p.Seconds * n,
+		Nanoseconds: p.Nanoseconds * n,
+	}
+}
+
+// ParseISOInterval takes an ISO 8601 time interval in any of its four forms: a start and an end
+// ("2007-03-01/2008-05-11"), a start and a duration ("2007-03-01T13:00:00Z/P1Y2M10DT2H30M"), a duration
+// and an end ("P1M/2008-05-11"), or a duration alone ("P1M"). An end may leave out the leading parts
+// it shares with the start, as in "2007-12-14T13:30/15:30", and takes the start's time zone when it has none
+// of its own. Instants without a time zone are otherwise in UTC.
+func ParseISOInterval(s string) (Interval, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+
+	switch len(parts) {
+	case 1:
+		p, err := ParseISODuration(parts[0])
+		return Interval{Period: p}, err
+	case 2:
+	default:
+		return Interval{}, fmt.Errorf(`strtotime: "%v" is not an ISO 8601 interval`, s)
+	}
+
+	startPeriod, startErr := ParseISODuration(parts[0])
+	endPeriod, endErr := ParseISODuration(parts[1])
+
+	switch {
+	case startErr == nil && endErr == nil:
+		return Interval{}, fmt.Errorf(`strtotime: "%v" is made of two durations`, s)
+	case endErr == nil:
+		start, err := parseISOInstant(parts[0])
+		if err != nil {
+			return Interval{}, err
+		}
+		return Interval{Start: start, End: endPeriod.AddTo(start), Period: endPeriod}, nil
+	case startErr == nil:
+		end, err := parseISOInstant(parts[1])
+		if err != nil {
+			return Interval{}, err
+		}
+		return Interval{Start: startPeriod.scale(-1).AddTo(end), End: end, Period: startPeriod}, nil
+	}
+
+	start, err := parseISOInstant(parts[0])
+	if err != nil {
+		return Interval{}, err
+	}
+
+	end, err := parseISOInstant(completeISOEnd(parts[0], parts[1]))
+	if err != nil {
+		return Interval{}, err
+	}
+
+	if end.Before(start) {
+		return Interval{}, fmt.Errorf(`strtotime: The interval "%v" ends before it starts`, s)
+	}
+
+	return Interval{Start: start, End: end}, nil
+}
+
+var isoZone = regexp.MustCompile(`(?:Z|[+-]\d\d(?::?\d\d)?)$`)
+
+// completeISOEnd fills in the date fields the end of an interval leaves out from those of its start, so that
+// "2007-11-13T09:00Z/15T17:00" ends at "2007-11-15T17:00Z". An end that is only a time of day takes the
+// whole date of the start, and an end without a time zone takes that of the start.
+func completeISOEnd(start, end string) string {
+	startDate, _, startZone := splitISOInstant(start)
+	endDate, endTime, endZone := splitISOInstant(end)
+
+	completed := endDate
+
+	if strings.Contains(startDate, "-") {
+		startFields, endFields := strings.Split(startDate, "-"), strings.Split(endDate, "-")
+		if endDate == "" {
+			endFields = nil
+		}
+		if n := len(startFields) - len(endFields); n > 0 {
+			completed = strings.Join(append(startFields[:n:n], endFields...), "-")
+		}
+	} else if len(endDate) < len(startDate) {
+		// the fields of a basic date such as "20070301" are told apart by where they are
+		completed = startDate[:len(startDate)-len(endDate)] + endDate
+	}
+
+	if endTime == "" {
+		return completed
+	}
+
+	if endZone == "" {
+		endZone = startZone
+	}
+
+	return completed + "T" + endTime + endZone
+}
+
+// splitISOInstant splits an instant into its date, its time of day, and the time zone of that time. An instant
+// without a "T" is a time of day if it has a colon, and a date otherwise.
+func splitISOInstant(s string) (date, clock, zone string) {
+	if i := strings.IndexAny(s, "Tt"); i >= 0 {
+		date, clock = s[:i], s[i+1:]
+	} else if strings.Contains(s, ":") {
+		clock = s
+	} else {
+		return s, "", ""
+	}
+
+	if loc := isoZone.FindStringIndex(clock); loc != nil {
+		clock, zone = clock[:loc[0]], clock[loc[0]:]
+	}
+
+	return date, clock, zone
+}
+
+// isoFormats are the formats of ISO 8601 dates, times and time zones, which are all parseISOInstant reads
+var isoFormats = func() []format {
+	names := map[string]bool{
+		"soap": true, "wddx": true, "xmlrpc": true, "xmlrpcnocolon": true, "iso8601long": true,
+		"gnudateshort | iso8601date2": true, "iso8601date4": true, "gnudateshorter": true, "datenocolon": true,
+		"isoweekday": true, "pgydotd": true, "timelong24": true, "timeshort24": true, "iso8601nocolon": true,
+		"gnunocolon": true, "tzcorrection": true, "utc": true,
+	}
+
+	var formats []format
+
+	for _, f := range allFormats {
+		if names[f.name] {
+			formats = append(formats, f)
+		}
+	}
+
+	return formats
+}()
+
+// parseISOInstant reads the instant at either end of an interval. It must have a date, and be written
+// as ISO 8601 writes them, so that "2007-03-01 +1 day" is not one.
+func parseISOInstant(s string) (time.Time, error) {
+	r, err := parseFormats(s, isoFormats)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	newOptions(nil).configure(r)
+
+	if r.dates == 0 {
+		return time.Time{}, fmt.Errorf(`strtotime: "%v" is not an ISO 8601 date`, s)
+	}
+
+	t, err := r.resolve(0)
+
+	return t, outOfRange(err, s)
+}
+
+// ParseISORepeatingInterval takes an ISO 8601 repeating interval - such as "R5/2008-03-01T13:00:00Z/P1Y2M10DT2H30M",
+// or "R/2008-03-01/P1W" to repeat forever - and returns it as a RepeatingInterval, or an error if the input is not one.
+// Intervals given as a duration and an end repeat backwards from that end, and so must have a number of repetitions.
+func ParseISORepeatingInterval(s string) (*RepeatingInterval, error) {
+	s = strings.TrimSpace(s)
+	i := strings.Index(s, "/")
+
+	if i < 0 || len(s) == 0 || (s[0] != 'R' && s[0] != 'r') {
+		return nil, fmt.Errorf(`strtotime: "%v" is not an ISO 8601 repeating interval`, s)
+	}
+
+	repetitions := -1
+
+	if count := s[1:i]; len(count) > 0 && count != "-1" {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf(`strtotime: "%v" is not a number of repetitions`, count)
+		}
+		repetitions = n
+	}
+
+	interval, err := ParseISOInterval(s[i+1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if interval.Start.IsZero() && interval.End.IsZero() {
+		return nil, fmt.Errorf(`strtotime: The repeating interval "%v" has neither a start nor an end`, s)
+	}
+
+	ri := &RepeatingInterval{
+		Interval:    interval,
+		Repetitions: repetitions,
+		backwards:   strings.HasPrefix(strings.ToUpper(s[i+1:]), "P") || strings.HasPrefix(s[i+1:], "-P"),
+	}
+
+	if ri.backwards && repetitions < 0 {
+		return nil, fmt.Errorf(`strtotime: The repeating interval "%v" ends, but never starts`, s)
+	}
+
+	return ri, nil
+}
+
+// start returns the start of the nth interval, counting from 0. Intervals with a duration add
+// it n times over in one go, so that monthly intervals starting on the 31st don't drift: they
+// overflow into the 1st of the next month after short months, and come back to the 31st.
+func (ri *RepeatingInterval) start(n int) time.Time {
+	if ri.Period == (Period{}) {
+		return ri.Interval.Start.Add(time.Duration(n) * ri.End.Sub(ri.Interval.Start))
+	}
+
+	if ri.backwards {
+		return ri.Period.scale(n - ri.Repetitions).AddTo(ri.End)
+	}
+
+	return ri.Period.scale(n).AddTo(ri.Interval.Start)
+}
+
+// Next returns the start of the first interval that starts strictly after the given time,
+// or the zero Time if there is none.
+func (ri *RepeatingInterval) Next(after time.Time) time.Time {
+	first := ri.start(0)
+	step := ri.start(1).Sub(first)
+
+	if step <= 0 {
+		return time.Time{}
+	}
+
+	// jump close to after, then walk the few remaining intervals,
+	// stepping back first in case intervals vary in length
+	n := 0
+	if after.After(first) {
+		n = int(after.Sub(first) / step)
+		for n > 0 && ri.start(n).After(after) {
+			n--
+		}
+	}
+
+	for ; ri.Repetitions < 0 || n < ri.Repetitions; n++ {
+		if t := ri.start(n); t.After(after) {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
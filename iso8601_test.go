@@ -0,0 +1,194 @@
+package strtotime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var isoDurationTests = []struct {
+	in  string
+	out Period
+	iso string
+}{
+	{"P3Y6M4DT12H30M5S", Period{Years: 3, Months: 6, Days: 4, Hours: 12, Minutes: 30, Seconds: 5}, "P3Y6M4DT12H30M5S"},
+	{"PT15M", Period{Minutes: 15}, "PT15M"},
+	{"P2W", Period{Days: 14}, "P14D"},
+	{"PT1.5H", Period{Hours: 1, Minutes: 30}, "PT1H30M"},
+	{"PT0,25S", Period{Nanoseconds: 250000000}, "PT0.25S"},
+	{"-P1D", Period{Days: -1}, "-P1D"},
+	{"P0D", Period{}, "PT0S"},
+}
+
+func TestParseISODuration(t *testing.T) {
+	for _, tt := range isoDurationTests {
+		t.Run(tt.in, func(t *testing.T) {
+			p, err := ParseISODuration(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p != tt.out {
+				t.Errorf("Period should have been %+v, but it was %+v", tt.out, p)
+			}
+			if p.String() != tt.iso {
+				t.Errorf("Duration should have been formatted as %v, but it was %v", tt.iso, p.String())
+			}
+		})
+	}
+}
+
+func TestParseISODurationRange(t *testing.T) {
+	for in, value := range map[string]string{
+		"P99999999999999999999Y":    "99999999999999999999",
+		"PT99999999999999999999.5H": "99999999999999999999",
+		"P1M99999999999999999999D":  "99999999999999999999",
+		"P9223372036854775807W":     "9223372036854775807",
+		"-PT99999999999999999999S":  "99999999999999999999",
+	} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseISODuration(in); !reflect.DeepEqual(err, &RangeError{Value: value}) {
+				t.Errorf("Error should have been %v, but it was %v", &RangeError{Value: value}, err)
+			}
+		})
+	}
+}
+
+func TestParseISODurationErrors(t *testing.T) {
+	for _, in := range []string{"P", "PT", "P1DT", "PT1.5H30M", "P1.5D", "1D", "P1S"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseISODuration(in); err == nil {
+				t.Errorf("%q should not have been a duration", in)
+			}
+		})
+	}
+}
+
+var isoIntervalTests = []struct {
+	in  string
+	out Interval
+}{
+	{"2007-03-01T13:00:00Z/P1Y2M10DT2H30M", Interval{
+		Start:  time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC),
+		End:    time.Date(2008, 5, 11, 15, 30, 0, 0, time.UTC),
+		Period: Period{Years: 1, Months: 2, Days: 10, Hours: 2, Minutes: 30},
+	}},
+	{"2007-03-01/2008-05-11", Interval{
+		Start: time.Date(2007, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2008, 5, 11, 0, 0, 0, 0, time.UTC),
+	}},
+	{"P1Y2M10DT2H30M/2008-05-11T15:30:00Z", Interval{
+		Start:  time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC),
+		End:    time.Date(2008, 5, 11, 15, 30, 0, 0, time.UTC),
+		Period: Period{Years: 1, Months: 2, Days: 10, Hours: 2, Minutes: 30},
+	}},
+	{"P1M", Interval{Period: Period{Months: 1}}},
+	{"2007-12-14T13:30/15:30", Interval{
+		Start: time.Date(2007, 12, 14, 13, 30, 0, 0, time.UTC),
+		End:   time.Date(2007, 12, 14, 15, 30, 0, 0, time.UTC),
+	}},
+	{"2008-02-15/03-14", Interval{
+		Start: time.Date(2008, 2, 15, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2008, 3, 14, 0, 0, 0, 0, time.UTC),
+	}},
+	{"2007-03-01T13:00:00Z/15:30Z", Interval{
+		Start: time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC),
+		End:   time.Date(2007, 3, 1, 15, 30, 0, 0, time.UTC),
+	}},
+	{"2007-03-01T13:00:00Z/15:30", Interval{
+		Start: time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC),
+		End:   time.Date(2007, 3, 1, 15, 30, 0, 0, time.UTC),
+	}},
+	{"2007-11-13T09:00Z/15T17:00", Interval{
+		Start: time.Date(2007, 11, 13, 9, 0, 0, 0, time.UTC),
+		End:   time.Date(2007, 11, 15, 17, 0, 0, 0, time.UTC),
+	}},
+	{"2007-03-01T13:00+05:00/15:30", Interval{
+		Start: time.Date(2007, 3, 1, 8, 0, 0, 0, time.UTC),
+		End:   time.Date(2007, 3, 1, 10, 30, 0, 0, time.UTC),
+	}},
+	{"20070301T1300Z/0302T1400", Interval{
+		Start: time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC),
+		End:   time.Date(2007, 3, 2, 14, 0, 0, 0, time.UTC),
+	}},
+}
+
+func TestParseISOInterval(t *testing.T) {
+	for _, tt := range isoIntervalTests {
+		t.Run(tt.in, func(t *testing.T) {
+			i, err := ParseISOInterval(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !i.Start.Equal(tt.out.Start) || !i.End.Equal(tt.out.End) || i.Period != tt.out.Period {
+				t.Errorf("Interval should have been %+v, but it was %+v", tt.out, i)
+			}
+		})
+	}
+}
+
+func TestParseISOIntervalErrors(t *testing.T) {
+	for _, in := range []string{"P1D/P2D", "2008-05-11/2007-03-01", "tomorrow/P1D", "2007-03-01/2008-05-11/P1D", "2007-03-01 +1 day/P1D", "P1D/next friday"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseISOInterval(in); err == nil {
+				t.Errorf("%q should not have been an interval", in)
+			}
+		})
+	}
+}
+
+var isoRepeatingTests = []struct {
+	in    string
+	after time.Time
+	out   []time.Time
+}{
+	{"R5/2008-03-01T13:00:00Z/P1Y2M10DT2H30M", time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC), []time.Time{
+		time.Date(2008, 3, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2009, 5, 11, 15, 30, 0, 0, time.UTC),
+		time.Date(2010, 7, 21, 18, 0, 0, 0, time.UTC),
+		time.Date(2011, 10, 1, 20, 30, 0, 0, time.UTC),
+		time.Date(2012, 12, 11, 23, 0, 0, 0, time.UTC),
+		{},
+	}},
+	{"R/2015-01-31/P1M", time.Date(2015, 6, 30, 0, 0, 0, 0, time.UTC), []time.Time{
+		time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 8, 31, 0, 0, 0, 0, time.UTC),
+	}},
+	{"R2/P1D/2015-07-05", time.Time{}, []time.Time{
+		time.Date(2015, 7, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 4, 0, 0, 0, 0, time.UTC),
+		{},
+	}},
+	{"R/2015-07-05T00:00:00Z/2015-07-05T06:00:00Z", time.Date(2015, 7, 10, 1, 0, 0, 0, time.UTC), []time.Time{
+		time.Date(2015, 7, 10, 6, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 10, 12, 0, 0, 0, time.UTC),
+	}},
+}
+
+func TestParseISORepeatingInterval(t *testing.T) {
+	for _, tt := range isoRepeatingTests {
+		t.Run(tt.in, func(t *testing.T) {
+			ri, err := ParseISORepeatingInterval(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			after := tt.after
+			for _, want := range tt.out {
+				after = ri.Next(after)
+				if !after.Equal(want) {
+					t.Fatalf("Interval should have started at %v, but it was %v", want, after)
+				}
+			}
+		})
+	}
+}
+
+func TestParseISORepeatingIntervalErrors(t *testing.T) {
+	for _, in := range []string{"2008-03-01/P1D", "R/P1D", "R/P1D/2008-03-01", "Rx/2008-03-01/P1D"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseISORepeatingInterval(in); err == nil {
+				t.Errorf("%q should not have been a repeating interval", in)
+			}
+		})
+	}
+}
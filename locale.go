@@ -0,0 +1,307 @@
+package strtotime
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Locale supplies the words and phrases of a language other than English. Parse reads them
+// as their English equivalents: "próxima sexta" in Portuguese is read as "next friday".
+// Words are matched whole, ignoring case and accents, so that "proxima" and "PRÓXIMA" are
+// both "próxima". A Locale is prepared the first time it is used, and changes to it after
+// that have no effect.
+type Locale struct {
+	// Name is the language's BCP 47 tag, such as "pt".
+	Name string
+
+	// Months holds the names of each month, full and abbreviated, from January.
+	Months [12][]string
+
+	// Weekdays holds the names of each day of the week, full and abbreviated, from Sunday.
+	Weekdays [7][]string
+
+	// Words maps any other word to its English equivalent, such as "amanhã" to "tomorrow" or
+	// "dias" to "days". Words mapped to "" are left out, as articles and prepositions often are.
+	Words map[string]string
+
+	// Phrases rewrite phrases whose order differs from English, such as "hace 3 days" to
+	// "3 days ago". They apply in order, once the words have been translated.
+	Phrases []Phrase
+
+	once         sync.Once
+	words        map[string]string
+	phrases      []*regexp.Regexp
+	replacements []string
+}
+
+// Phrase is a regular expression, matched without regard to case, and its replacement,
+// which may refer to the expression's groups as regexp.ReplaceAllString does.
+type Phrase struct {
+	Pattern     string
+	Replacement string
+}
+
+// English is the parser's own language, and needs no translation.
+var English = &Locale{Name: "en"}
+
+// postposed moves "next", "last" and "this" in front of the unit or day they follow,
+// as in "semana passada" or "lundi prochain"
+var postposed = Phrase{
+	`\b(sec(?:ond)?s?|min(?:ute)?s?|hours?|days?|weeks?|fortnights?|months?|years?|monday|tuesday|wednesday|thursday|friday|saturday|sunday)[ ]+(next|last|this)\b`,
+	"$2 $1",
+}
+
+// hoursMinutes and wholeHours read times written with an "h", as in "15h30" and "15h"
+var (
+	hoursMinutes = Phrase{`\b([01]?[0-9]|2[0-3])\s*h\s*([0-5][0-9])\b`, "$1:$2"}
+	wholeHours   = Phrase{`\b([01]?[0-9]|2[0-3])\s*h\b`, "$1:00"}
+)
+
+// Portuguese is Brazilian and European Portuguese.
+var Portuguese = &Locale{
+	Name: "pt",
+	Months: [12][]string{
+		{"janeiro", "jan"}, {"fevereiro", "fev"}, {"março", "mar"}, {"abril", "abr"},
+		{"maio", "mai"}, {"junho", "jun"}, {"julho", "jul"}, {"agosto", "ago"},
+		{"setembro", "set"}, {"outubro", "out"}, {"novembro", "nov"}, {"dezembro", "dez"},
+	},
+	Weekdays: [7][]string{
+		{"domingo", "dom"},
+		{"segunda-feira", "segunda", "seg"},
+		{"terça-feira", "terça", "ter"},
+		{"quarta-feira", "quarta", "qua"},
+		{"quinta-feira", "quinta", "qui"},
+		{"sexta-feira", "sexta", "sex"},
+		{"sábado", "sáb"},
+	},
+	Words: map[string]string{
+		"agora": "now", "hoje": "today", "amanhã": "tomorrow", "ontem": "yesterday",
+		"meio-dia": "noon", "meia-noite": "midnight",
+		"próximo": "next", "próxima": "next", "último": "last", "última": "last",
+		"passado": "last", "passada": "last", "este": "this", "esta": "this", "nesta": "this", "neste": "this",
+		"segundo": "second", "segundos": "seconds", "minuto": "minute", "minutos": "minutes", "hora": "hour", "horas": "hours",
+		"dia": "day", "dias": "days", "semana": "week", "semanas": "weeks",
+		"mês": "month", "meses": "months", "ano": "year", "anos": "years",
+		"em": "in", "daqui": "in", "às": "at", "as": "at", "a": "", "de": "", "do": "", "da": "", "no": "", "na": "",
+		"o": "",
+	},
+	Phrases: []Phrase{
+		{`(^|\s)há\s+(\d+\s+\pL+)`, "$1$2 ago"},
+		{`(\d+\s+\pL+)\s+atrás\b`, "$1 ago"},
+		{`(\pL+)\s+que\s+vem\b`, "next $1"},
+		hoursMinutes,
+		wholeHours,
+		postposed,
+	},
+}
+
+// Spanish is Spanish as written in Spain and Latin America.
+var Spanish = &Locale{
+	Name: "es",
+	Months: [12][]string{
+		{"enero", "ene"}, {"febrero", "feb"}, {"marzo", "mar"}, {"abril", "abr"},
+		{"mayo", "may"}, {"junio", "jun"}, {"julio", "jul"}, {"agosto", "ago"},
+		{"septiembre", "setiembre", "sep", "sept", "set"}, {"octubre", "oct"}, {"noviembre", "nov"}, {"diciembre", "dic"},
+	},
+	Weekdays: [7][]string{
+		{"domingo", "dom"}, {"lunes", "lun"}, {"martes"}, {"miércoles", "mié"},
+		{"jueves", "jue"}, {"viernes", "vie"}, {"sábado", "sáb"},
+	},
+	Words: map[string]string{
+		"ahora": "now", "hoy": "today", "mañana": "tomorrow", "ayer": "yesterday",
+		"mediodía": "noon", "medianoche": "midnight",
+		"próximo": "next", "próxima": "next", "siguiente": "next", "último": "last", "última": "last",
+		"pasado": "last", "pasada": "last", "este": "this", "esta": "this",
+		"segundo": "second", "segundos": "seconds", "minuto": "minute", "minutos": "minutes", "hora": "hour", "horas": "hours",
+		"día": "day", "días": "days", "semana": "week", "semanas": "weeks",
+		"mes": "month", "meses": "months", "año": "year", "años": "years",
+		"en": "in", "dentro": "in", "a": "at", "de": "", "del": "", "el": "", "la": "", "las": "", "los": "",
+	},
+	Phrases: []Phrase{
+		{`(^|\s)hace\s+(\d+\s+\pL+)`, "$1$2 ago"},
+		{`(\pL+)\s+que\s+viene\b`, "next $1"},
+		// "pasado mañana" has been read word by word as "last tomorrow" by now
+		{`\blast\s+tomorrow\b`, "tomorrow +1 day"},
+		postposed,
+	},
+}
+
+// French is French as written in France.
+var French = &Locale{
+	Name: "fr",
+	Months: [12][]string{
+		{"janvier", "janv"}, {"février", "févr", "fév"}, {"mars"}, {"avril", "avr"},
+		{"mai"}, {"juin"}, {"juillet", "juil"}, {"août"},
+		{"septembre", "sept"}, {"octobre", "oct"}, {"novembre", "nov"}, {"décembre", "déc"},
+	},
+	Weekdays: [7][]string{
+		{"dimanche", "dim"}, {"lundi", "lun"}, {"mardi", "mar"}, {"mercredi", "mer"},
+		{"jeudi", "jeu"}, {"vendredi", "ven"}, {"samedi", "sam"},
+	},
+	Words: map[string]string{
+		"maintenant": "now", "aujourd'hui": "today", "demain": "tomorrow", "hier": "yesterday",
+		"après-demain": "tomorrow +1 day", "midi": "noon", "minuit": "midnight",
+		"prochain": "next", "prochaine": "next", "dernier": "last", "dernière": "last",
+		"précédent": "last", "précédente": "last", "ce": "this", "cette": "this",
+		"seconde": "second", "secondes": "seconds", "heure": "hour", "heures": "hours",
+		"jour": "day", "jours": "days", "semaine": "week", "semaines": "weeks",
+		"mois": "months", "an": "year", "ans": "years", "année": "year", "années": "years",
+		"dans": "in", "à": "at", "le": "", "la": "", "l'": "", "de": "", "du": "",
+	},
+	Phrases: []Phrase{
+		// the "a" of "il y a" is read as "à", and so is "at" by now
+		{`(^|\s)il\s+y\s+at\s+(\d+\s+\pL+)`, "$1$2 ago"},
+		hoursMinutes,
+		wholeHours,
+		postposed,
+	},
+}
+
+// German is German as written in Germany and Austria.
+var German = &Locale{
+	Name: "de",
+	Months: [12][]string{
+		{"januar", "jänner", "jan"}, {"februar", "feb"}, {"märz", "mär"}, {"april", "apr"},
+		{"mai"}, {"juni", "jun"}, {"juli", "jul"}, {"august", "aug"},
+		{"september", "sep", "sept"}, {"oktober", "okt"}, {"november", "nov"}, {"dezember", "dez"},
+	},
+	Weekdays: [7][]string{
+		{"sonntag"}, {"montag"}, {"dienstag"}, {"mittwoch"},
+		{"donnerstag"}, {"freitag"}, {"samstag", "sonnabend"},
+	},
+	Words: map[string]string{
+		"jetzt": "now", "heute": "today", "morgen": "tomorrow", "gestern": "yesterday",
+		"übermorgen": "tomorrow +1 day", "mittag": "noon", "mitternacht": "midnight",
+		"nächste": "next", "nächsten": "next", "nächster": "next", "nächstes": "next",
+		"kommende": "next", "kommenden": "next", "kommender": "next", "kommendes": "next",
+		"letzte": "last", "letzten": "last", "letzter": "last", "letztes": "last",
+		"vorige": "last", "vorigen": "last", "voriger": "last", "voriges": "last",
+		"diese": "this", "diesen": "this", "dieser": "this", "dieses": "this",
+		"sekunde": "second", "sekunden": "seconds", "minuten": "minutes", "stunde": "hour", "stunden": "hours",
+		"tag": "day", "tage": "days", "tagen": "days", "woche": "week", "wochen": "weeks",
+		"monat": "month", "monate": "months", "monaten": "months", "jahr": "year", "jahre": "years", "jahren": "years",
+		"um": "at", "den": "", "der": "", "dem": "",
+	},
+	Phrases: []Phrase{
+		{`(^|\s)vor\s+(\d+\s+\pL+)`, "$1$2 ago"},
+		{`\b([01]?[0-9]|2[0-3]):([0-5][0-9])\s*uhr\b`, "$1:$2"},
+		{`\b([01]?[0-9]|2[0-3])\s*uhr\s+([0-5][0-9])\b`, "$1:$2"},
+		{`\b([01]?[0-9]|2[0-3])\s*uhr\b`, "$1:00"},
+		// "am Montag", but not "9 am"
+		{`(^|\pL[,.]?\s+)am\s+(\pL)`, "$1$2"},
+	},
+}
+
+// accents folds accented letters into their plain equivalents
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"ç", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ñ", "n",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ß", "ss", "œ", "oe", "’", "'",
+)
+
+// fold returns the word as it is looked up: in lower case and without accents
+func fold(word string) string {
+	return accents.Replace(strings.ToLower(word))
+}
+
+// prepare folds the words of the locale into a single map, and compiles its phrases
+func (l *Locale) prepare() {
+	l.once.Do(func() {
+		l.words = map[string]string{}
+
+		for w, english := range l.Words {
+			l.words[fold(w)] = english
+		}
+
+		for i, names := range l.Weekdays {
+			for _, name := range names {
+				l.words[fold(name)] = strings.ToLower(time.Weekday(i).String())
+			}
+		}
+
+		for i, names := range l.Months {
+			for _, name := range names {
+				l.words[fold(name)] = strings.ToLower(time.Month(i + 1).String())
+			}
+		}
+
+		for _, p := range l.Phrases {
+			l.phrases = append(l.phrases, regexp.MustCompile("(?i)"+p.Pattern))
+			l.replacements = append(l.replacements, p.Replacement)
+		}
+	})
+}
+
+// lookup returns the English equivalent of a word of the locale
+func (l *Locale) lookup(word string) (string, bool) {
+	l.prepare()
+
+	english, ok := l.words[fold(word)]
+
+	return english, ok
+}
+
+// translate returns s in English: the words of the locale are replaced by their English
+// equivalents, and then its phrases are rewritten.
+func (l *Locale) translate(s string) string {
+	if l == nil || l == English {
+		return s
+	}
+
+	var b strings.Builder
+
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		if !unicode.IsLetter(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		// words run on through hyphens and apostrophes between letters, as in "aujourd'hui"
+		j := i + 1
+		for j < len(runes) && (unicode.IsLetter(runes[j]) || (strings.ContainsRune("-'’", runes[j]) && j+1 < len(runes) && unicode.IsLetter(runes[j+1]))) {
+			j++
+		}
+
+		word := string(runes[i:j])
+
+		// elided articles, such as the "l'" of "l'an", stand apart from their word
+		if k := strings.IndexAny(word, "'’"); k > 0 {
+			if english, ok := l.lookup(word[:k+1]); ok {
+				if _, whole := l.lookup(word); !whole {
+					b.WriteString(english + " ")
+					i += len([]rune(word[:k])) + 1
+					continue
+				}
+			}
+		}
+
+		if english, ok := l.lookup(word); ok {
+			b.WriteString(english)
+		} else {
+			b.WriteString(word)
+		}
+
+		i = j
+	}
+
+	s = b.String()
+
+	l.prepare()
+
+	for i, p := range l.phrases {
+		s = p.ReplaceAllString(s, l.replacements[i])
+	}
+
+	return s
+}
@@ -0,0 +1,107 @@
+package strtotime
+
+import (
+	"strings"
+	"testing"
+)
+
+var localeTests = []struct {
+	locale  *Locale
+	in      string
+	english string
+}{
+	{Portuguese, "próxima sexta", "next friday"},
+	{Portuguese, "proxima SEXTA", "next friday"},
+	{Portuguese, "sexta-feira que vem", "next friday"},
+	{Portuguese, "amanhã às 15:30", "tomorrow 15:30"},
+	{Portuguese, "ontem meio-dia", "yesterday noon"},
+	{Portuguese, "há 3 dias", "3 days ago"},
+	{Portuguese, "2 horas atrás", "2 hours ago"},
+	{Portuguese, "daqui a 2 semanas", "+2 weeks"},
+	{Portuguese, "semana passada", "last week"},
+	{Portuguese, "3 de março de 2016", "3 march 2016"},
+	{Portuguese, "amanhã às 15h30", "tomorrow 15:30"},
+	{Portuguese, "hoje 9h", "today 9:00"},
+	{Spanish, "hace 3 días", "3 days ago"},
+	{Spanish, "el próximo viernes", "next friday"},
+	{Spanish, "el lunes pasado", "last monday"},
+	{Spanish, "mañana a las 9:00", "tomorrow 9:00"},
+	{Spanish, "la semana que viene", "next week"},
+	{Spanish, "dentro de 2 horas", "+2 hours"},
+	{Spanish, "15 de agosto de 2016", "15 august 2016"},
+	{Spanish, "pasado mañana", "tomorrow +1 day"},
+	{Spanish, "pasado mañana a las 9:00", "tomorrow +1 day 9:00"},
+	{French, "demain 15h", "tomorrow 15:00"},
+	{French, "aujourd'hui 9h30", "today 9:30"},
+	{French, "lundi prochain", "next monday"},
+	{French, "il y a 2 semaines", "2 weeks ago"},
+	{French, "dans 3 jours", "+3 days"},
+	{French, "l'an prochain", "next year"},
+	{French, "14 juillet 2016", "14 july 2016"},
+	{French, "après-demain 15h", "tomorrow +1 day 15:00"},
+	{German, "nächsten Montag", "next monday"},
+	{German, "am Freitag", "friday"},
+	{German, "morgen um 15 Uhr", "tomorrow 15:00"},
+	{German, "heute 9 Uhr 30", "today 9:30"},
+	{German, "15:30 Uhr", "15:30"},
+	{German, "übermorgen um 15:30 Uhr", "tomorrow +1 day 15:30"},
+	{German, "heute 9 am", "today 9am"},
+	{German, "vor 3 Tagen", "3 days ago"},
+	{German, "in 2 Stunden", "+2 hours"},
+	{German, "letzte Woche", "last week"},
+	{German, "3. März 2016", "3 march 2016"},
+}
+
+func TestInLocale(t *testing.T) {
+	for _, tt := range localeTests {
+		t.Run(tt.locale.Name+" "+tt.in, func(t *testing.T) {
+			want, err := Parse(tt.english, now.Unix())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Parse(tt.in, now.Unix(), InLocale(tt.locale))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("%q should have been read as %q", tt.in, tt.english)
+			}
+		})
+	}
+}
+
+func TestInLocaleKeepsToItsWords(t *testing.T) {
+	for _, tt := range []struct {
+		locale *Locale
+		in     string
+	}{
+		{English, "amanhã"},
+		{German, "demain"},
+		{French, "mañana"},
+	} {
+		if _, err := Parse(tt.in, now.Unix(), InLocale(tt.locale)); err == nil {
+			t.Errorf("%q should not have been understood in %v", tt.in, tt.locale.Name)
+		}
+	}
+}
+
+func TestInLocaleQuotesInput(t *testing.T) {
+	for _, tt := range []struct {
+		locale *Locale
+		in     string
+	}{
+		{Spanish, "pasado xyz"},
+		{German, "morgen xyz"},
+	} {
+		_, err := Parse(tt.in, now.Unix(), InLocale(tt.locale))
+		if err == nil {
+			t.Fatalf("%q should not have been understood in %v", tt.in, tt.locale.Name)
+		}
+		if want := `strtotime: Unrecognizable input: "` + tt.in + `"`; err.Error() != want {
+			t.Errorf("The error for %q should have been %v, but it was %v", tt.in, want, err)
+		}
+		if _, err := ParseAll(tt.in, now.Unix(), InLocale(tt.locale)); err == nil || !strings.Contains(err.Error(), tt.in) {
+			t.Errorf("The ParseAll error for %q should have quoted it, but it was %v", tt.in, err)
+		}
+	}
+}
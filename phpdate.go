@@ -0,0 +1,156 @@
+package strtotime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatPHP formats t the way PHP's date() does, such as FormatPHP("Y-m-d H:i:s", t) for "2004-02-12 15:19:21".
+// Every character of PHP's format is supported, and any other character is copied as is. A backslash copies
+// the character after it as is, so that FormatPHP(`l \t\h\e jS`, t) gives "Thursday the 12th".
+func FormatPHP(layout string, t time.Time) string {
+	var b strings.Builder
+
+	escaped := false
+
+	for _, c := range layout {
+		if escaped {
+			b.WriteRune(c)
+			escaped = false
+			continue
+		}
+
+		switch c {
+		case '\\':
+			escaped = true
+		// day
+		case 'd':
+			b.WriteString(t.Format("02"))
+		case 'D':
+			b.WriteString(t.Format("Mon"))
+		case 'j':
+			b.WriteString(strconv.Itoa(t.Day()))
+		case 'l':
+			b.WriteString(t.Weekday().String())
+		case 'N':
+			b.WriteString(strconv.Itoa((int(t.Weekday())+6)%7 + 1))
+		case 'S':
+			b.WriteString(strings.TrimPrefix(ordinal(t.Day()), strconv.Itoa(t.Day())))
+		case 'w':
+			b.WriteString(strconv.Itoa(int(t.Weekday())))
+		case 'z':
+			b.WriteString(strconv.Itoa(t.YearDay() - 1))
+		// week
+		case 'W':
+			_, week := t.ISOWeek()
+			fmt.Fprintf(&b, "%02d", week)
+		// month
+		case 'F':
+			b.WriteString(t.Month().String())
+		case 'm':
+			b.WriteString(t.Format("01"))
+		case 'M':
+			b.WriteString(t.Format("Jan"))
+		case 'n':
+			b.WriteString(strconv.Itoa(int(t.Month())))
+		case 't':
+			b.WriteString(strconv.Itoa(daysIn(t.Year(), t.Month())))
+		// year
+		case 'L':
+			if daysIn(t.Year(), time.February) == 29 {
+				b.WriteString("1")
+			} else {
+				b.WriteString("0")
+			}
+		case 'o':
+			year, _ := t.ISOWeek()
+			b.WriteString(phpYear(year))
+		case 'Y':
+			b.WriteString(phpYear(t.Year()))
+		case 'y':
+			b.WriteString(t.Format("06"))
+		// time
+		case 'a':
+			b.WriteString(t.Format("pm"))
+		case 'A':
+			b.WriteString(t.Format("PM"))
+		case 'B':
+			// Swatch Internet time counts thousandths of a day in UTC+1
+			u := t.UTC()
+			seconds := (u.Hour()*3600 + u.Minute()*60 + u.Second() + 3600) % 86400
+			fmt.Fprintf(&b, "%03d", seconds*1000/86400)
+		case 'g':
+			b.WriteString(t.Format("3"))
+		case 'G':
+			b.WriteString(strconv.Itoa(t.Hour()))
+		case 'h':
+			b.WriteString(t.Format("03"))
+		case 'H':
+			b.WriteString(t.Format("15"))
+		case 'i':
+			b.WriteString(t.Format("04"))
+		case 's':
+			b.WriteString(t.Format("05"))
+		case 'u':
+			fmt.Fprintf(&b, "%06d", t.Nanosecond()/1000)
+		case 'v':
+			fmt.Fprintf(&b, "%03d", t.Nanosecond()/1000000)
+		// time zone
+		case 'e':
+			b.WriteString(t.Location().String())
+		case 'I':
+			if t.IsDST() {
+				b.WriteString("1")
+			} else {
+				b.WriteString("0")
+			}
+		case 'O':
+			b.WriteString(t.Format("-0700"))
+		case 'P':
+			b.WriteString(t.Format("-07:00"))
+		case 'p':
+			if _, offset := t.Zone(); offset == 0 {
+				b.WriteString("Z")
+			} else {
+				b.WriteString(t.Format("-07:00"))
+			}
+		case 'T':
+			// zones without an abbreviation go by their offset, as in PHP
+			if name, _ := t.Zone(); len(name) > 0 && !strings.ContainsAny(name, "+-") {
+				b.WriteString(name)
+			} else {
+				b.WriteString(t.Format("-07:00"))
+			}
+		case 'Z':
+			_, offset := t.Zone()
+			b.WriteString(strconv.Itoa(offset))
+		// full date/time
+		case 'c':
+			b.WriteString(FormatPHP("Y-m-d\\TH:i:sP", t))
+		case 'r':
+			b.WriteString(FormatPHP("D, d M Y H:i:s O", t))
+		case 'U':
+			b.WriteString(strconv.FormatInt(t.Unix(), 10))
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	// a trailing backslash has nothing to escape, and is kept
+	if escaped {
+		b.WriteRune('\\')
+	}
+
+	return b.String()
+}
+
+// phpYear formats a year with at least 4 digits, and a minus sign for years before year 0
+func phpYear(year int) string {
+	if year < 0 {
+		return fmt.Sprintf("-%04d", -year)
+	}
+
+	return fmt.Sprintf("%04d", year)
+}
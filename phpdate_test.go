@@ -0,0 +1,47 @@
+package strtotime
+
+import (
+	"testing"
+	"time"
+)
+
+var phpTime = time.Date(2001, 3, 10, 17, 16, 18, 0, time.UTC)
+
+var formatPHPTests = []struct {
+	layout string
+	t      time.Time
+	out    string
+}{
+	{"F j, Y, g:i a", phpTime, "March 10, 2001, 5:16 pm"},
+	{"m.d.y", phpTime, "03.10.01"},
+	{"j, n, Y", phpTime, "10, 3, 2001"},
+	{"Ymd", phpTime, "20010310"},
+	{`h-i-s, j-m-y, it is w Day`, phpTime, "05-16-18, 10-03-01, 1631 1618 6 Satpm01"},
+	{`\i\t \i\s \t\h\e jS \d\a\y.`, phpTime, "it is the 10th day."},
+	{"D M j G:i:s T Y", phpTime, "Sat Mar 10 17:16:18 UTC 2001"},
+	{`H:m:s \m \i\s\ \m\o\n\t\h`, phpTime, "17:03:18 m is month"},
+	{"Y-m-d H:i:s", phpTime, "2001-03-10 17:16:18"},
+	{"N z W t L B", phpTime, "6 68 10 31 0 761"},
+	{"c", phpTime, "2001-03-10T17:16:18+00:00"},
+	{"r", phpTime, "Sat, 10 Mar 2001 17:16:18 +0000"},
+	{"U", phpTime, "984244578"},
+	{"p", phpTime, "Z"},
+	{"jS jS jS", time.Date(2004, 2, 22, 0, 0, 0, 0, time.UTC), "22nd 22nd 22nd"},
+	{"jS l", time.Date(2004, 2, 11, 0, 0, 0, 0, time.UTC), "11th Wednesday"},
+	{"u v O P p T Z e I L", time.Date(2004, 2, 12, 15, 19, 21, 123456789, time.FixedZone("IST", 19800)), "123456 123 +0530 +05:30 +05:30 IST 19800 IST 0 1"},
+	{"T", time.Date(2004, 2, 12, 15, 19, 21, 0, time.FixedZone("", -3600)), "-01:00"},
+	{`o-\WW`, time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC), "2004-W53"},
+	{"Y y", time.Date(987, 1, 1, 0, 0, 0, 0, time.UTC), "0987 87"},
+	{`Y\`, phpTime, `2001\`},
+}
+
+func TestFormatPHP(t *testing.T) {
+	for _, tt := range formatPHPTests {
+		t.Run(tt.layout, func(t *testing.T) {
+			out := FormatPHP(tt.layout, tt.t)
+			if out != tt.out {
+				t.Errorf("FormatPHP should have returned %q, but it returned %q", tt.out, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package strtotime
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// RangeError is the error Parse returns for a number too large to count with, as in
+// "@99999999999999999999", or for a time too far from 1970 for a time.Time to hold, as
+// "+999999999999 years" is.
+type RangeError struct {
+	// Value is the number that is out of range, or the whole string when the time it
+	// refers to is
+	Value string
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf(`strtotime: "%v" is out of range`, e.Value)
+}
+
+// the earliest and latest unix times a time.Time holds without its date wrapping around: the first
+// second of the year -292277022399, and the last one of December 4th 292277026596
+const (
+	minUnix = -9223372028715321600
+	maxUnix = math.MaxInt64
+)
+
+// addShift adds amount times unit to the shift at p. It reports false, leaving the shift as it
+// is, when the sum doesn't fit in an int.
+func addShift(p *int, amount, unit int) bool {
+	const max = int(^uint(0) >> 1)
+
+	if amount < -max || amount != 0 && (unit > max/abs(amount) || unit < -max/abs(amount)) {
+		return false
+	}
+
+	n := amount * unit
+
+	if n > 0 && *p > max-n || n < 0 && *p < -max-n {
+		return false
+	}
+
+	*p += n
+	return true
+}
+
+// abs returns the absolute value of n, which must be greater than the smallest int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// inRange reports whether the date and time, which may lie outside their usual ranges, as time.Date
+// takes them, fall between minUnix and maxUnix. It works out roughly how far they are from 1970, as
+// time.Date would wrap around rather than fail.
+func inRange(y, m, d, h, i, s int) bool {
+	const secondsPerYear = 31556952
+
+	estimate := (float64(y)-1970)*secondsPerYear + float64(m)*secondsPerYear/12 + float64(d)*86400 +
+		float64(h)*3600 + float64(i)*60 + float64(s)
+
+	// a margin for the error of the estimate, of a few days at most, which is far less than the margin
+	// itself, and far less than how much a time that wraps around is off by
+	const margin = 1e12
+
+	if estimate < minUnix-margin || estimate > maxUnix+margin {
+		return false
+	}
+
+	u := time.Date(y, time.January+time.Month(m), d, h, i, s, 0, time.UTC).Unix()
+
+	return u >= minUnix && math.Abs(float64(u)-estimate) < margin
+}
@@ -0,0 +1,99 @@
+package strtotime
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var rangeTests = []struct {
+	in  string
+	out int64
+	err *RangeError
+}{
+	{"@9223372036854775807", math.MaxInt64, nil},
+	{"@-9223372028715321600", minUnix, nil},
+	{"+9999999999 years", time.Date(10000002014, 7, 5, 13, 0, 0, 0, time.UTC).Unix(), nil},
+	{"292277024581 years", time.Date(292277026596, 7, 5, 13, 0, 0, 0, time.UTC).Unix(), nil},
+
+	{"@99999999999999999999", 0, &RangeError{Value: "99999999999999999999"}},
+	{"@-9223372036854775808", 0, &RangeError{Value: "-9223372036854775808"}},
+	{"@-9223372028715321601", 0, &RangeError{Value: "@-9223372028715321601"}},
+	{"@9223372036854775807 +1 sec", 0, &RangeError{Value: "+1 sec"}},
+	{"+99999999999999999999 days", 0, &RangeError{Value: "99999999999999999999"}},
+	{"+9223372036854775807 weeks", 0, &RangeError{Value: "+9223372036854775807 weeks"}},
+	{"+9223372036854775807 sec +9223372036854775807 sec", 0, &RangeError{Value: "+9223372036854775807 sec"}},
+	{"+292277024582 years", 0, &RangeError{Value: "+292277024582 years"}},
+	{"-99999999999999 years", 0, &RangeError{Value: "-99999999999999 years"}},
+	{"9223372036854775807 sec ago", 0, &RangeError{Value: "9223372036854775807 sec ago"}},
+	{"+9223372036854775807 ms", 0, &RangeError{Value: "+9223372036854775807 ms"}},
+	{"@9223372036854775807 +1000000000 ns", 0, &RangeError{Value: "@9223372036854775807 +1000000000 ns"}},
+}
+
+func TestRange(t *testing.T) {
+	for _, tt := range rangeTests {
+		t.Run(tt.in, func(t *testing.T) {
+			u, err := Parse(tt.in, now.Unix())
+
+			if tt.err == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if u != tt.out {
+					t.Errorf("Result should have been %v, but it was %v", tt.out, u)
+				}
+				return
+			}
+
+			if e, ok := err.(*RangeError); !ok || *e != *tt.err {
+				t.Errorf("Error should have been %v, but it was %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestRangeElsewhere(t *testing.T) {
+	e, err := Compile("+292277024582 years")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u := e.Eval(now); !u.IsZero() {
+		t.Errorf("Eval should have returned the zero Time, but it returned %v", u)
+	}
+
+	if _, _, err := ParsePrefix("+292277024582 years later", now.Unix()); err == nil {
+		t.Error("ParsePrefix should have failed")
+	}
+
+	if _, err := ParseAll("+292277024582 years", now.Unix()); err == nil {
+		t.Error("ParseAll should have failed")
+	}
+
+	if m := FindAll("in +292277024582 years or tomorrow", now.Unix()); len(m) != 1 || m[0].Text != "tomorrow" {
+		t.Errorf("FindAll should only have found tomorrow, but it found %+v", m)
+	}
+}
+
+func TestAddShift(t *testing.T) {
+	const max = int(^uint(0) >> 1)
+
+	for _, tt := range []struct {
+		shift, amount, unit int
+		ok                  bool
+	}{
+		{0, max, 1, true},
+		{1, max, 1, false},
+		{0, max/7 + 1, 7, false},
+		{0, -max / 7, 7, true},
+		{-1, -max, 1, false},
+		{0, -max - 1, 1, false},
+	} {
+		shift := tt.shift
+
+		if ok := addShift(&shift, tt.amount, tt.unit); ok != tt.ok {
+			t.Errorf("Adding %v times %v to %v should have been %v", tt.amount, tt.unit, tt.shift, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,727 @@
+package strtotime
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the unit of time a Recurrence repeats on.
+type Frequency int
+
+// Supported frequencies, from the finest to the coarsest.
+const (
+	Secondly Frequency = iota
+	Minutely
+	Hourly
+	Daily
+	Weekly
+	Monthly
+	Yearly
+)
+
+// WeekdayNum is a weekday a Recurrence falls on. When N is not zero, only the Nth such
+// weekday of the month matches, counting backwards from the end of the month when N is
+// negative: {time.Friday, -1} is the last Friday of the month.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Recurrence is a repeating schedule, such as "every other Friday at 9am".
+// Start is the first occurrence, and counts towards Count, even when it doesn't match the
+// rule, as DTSTART does in RFC 5545. No occurrence comes before it, and for frequencies of
+// Daily or coarser they all happen at its clock time. The fields mirror the parts of an RFC 5545 RRULE
+// of the same name; WeekStart only matters to Weekly recurrences with an Interval above 1.
+type Recurrence struct {
+	Start      time.Time
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+
+	// Count limits the number of occurrences when not zero, and Until
+	// is the last time an occurrence may happen when not the zero Time.
+	Count int
+	Until time.Time
+}
+
+// maxPeriods bounds the search for the next occurrence, so that rules that can never
+// match - such as the 31st of every other month, starting in April - still terminate.
+const maxPeriods = 100000
+
+// Next returns the first occurrence strictly after the given time, or the zero Time if
+// there is none.
+func (rec *Recurrence) Next(after time.Time) time.Time {
+	if !rec.Until.IsZero() && rec.Start.After(rec.Until) {
+		return time.Time{}
+	}
+
+	if rec.Start.After(after) {
+		return rec.Start
+	}
+
+	return rec.next(after, 1)
+}
+
+// next returns the first time after the given one that matches the rule, or the zero Time.
+// seen is the number of occurrences that come before those of the rule: 1 for Start, or 0
+// to find the first time the rule matches.
+func (rec *Recurrence) next(after time.Time, seen int) time.Time {
+	interval := rec.Interval
+
+	if interval < 1 {
+		interval = 1
+	}
+
+	// a count can only be honored by walking every occurrence since Start
+	k := 0
+	if rec.Count == 0 {
+		k = rec.firstPeriod(after, interval)
+	}
+
+	for n := 0; n < maxPeriods; n++ {
+		occurrences := rec.expand(k * interval)
+
+		for _, t := range occurrences {
+			// Start has been counted already, when seen is not zero
+			if t.Before(rec.Start) || seen > 0 && t.Equal(rec.Start) {
+				continue
+			}
+
+			if !rec.Until.IsZero() && t.After(rec.Until) {
+				return time.Time{}
+			}
+
+			seen++
+
+			if rec.Count > 0 && seen > rec.Count {
+				return time.Time{}
+			}
+
+			if t.After(after) {
+				return t
+			}
+		}
+
+		// periods shorter than a day are filtered by day one at a time,
+		// so jump straight to the first period of the following day
+		if rec.Freq < Daily && len(occurrences) == 0 {
+			step := time.Duration(interval) * rec.Freq.duration()
+			y, m, d := rec.Start.Add(time.Duration(k) * step).Date()
+			midnight := time.Date(y, m, d+1, 0, 0, 0, 0, rec.Start.Location())
+			k = int((midnight.Sub(rec.Start) + step - 1) / step)
+			continue
+		}
+
+		k++
+	}
+
+	return time.Time{}
+}
+
+// firstPeriod estimates the index of a period starting no later than after, so that
+// Next doesn't have to walk every period since Start.
+func (rec *Recurrence) firstPeriod(after time.Time, interval int) int {
+	if !after.After(rec.Start) {
+		return 0
+	}
+
+	var periods int
+
+	switch rec.Freq {
+	case Secondly, Minutely, Hourly, Daily, Weekly:
+		periods = int(after.Sub(rec.Start) / rec.Freq.duration())
+	case Monthly:
+		periods = (after.Year()-rec.Start.Year())*12 + int(after.Month()) - int(rec.Start.Month())
+	case Yearly:
+		periods = after.Year() - rec.Start.Year()
+	}
+
+	// one period of slack makes up for daylight saving changes and partial weeks
+	k := periods/interval - 1
+
+	if k < 0 {
+		return 0
+	}
+
+	return k
+}
+
+// expand returns, in chronological order, the candidate occurrences of the nth period
+// after Start, a period being one unit of the recurrence frequency.
+func (rec *Recurrence) expand(n int) []time.Time {
+	y, m, d := rec.Start.Date()
+	hh, mm, ss := rec.Start.Clock()
+	ns, loc := rec.Start.Nanosecond(), rec.Start.Location()
+
+	var occurrences []time.Time
+
+	switch rec.Freq {
+	case Secondly, Minutely, Hourly:
+		t := rec.Start.Add(time.Duration(n) * rec.Freq.duration())
+		if rec.matchesMonth(t.Month()) && rec.matchesDay(t) {
+			occurrences = append(occurrences, t)
+		}
+	case Daily:
+		t := time.Date(y, m, d+n, hh, mm, ss, ns, loc)
+		if rec.matchesMonth(t.Month()) && rec.matchesDay(t) {
+			occurrences = append(occurrences, t)
+		}
+	case Weekly:
+		weekStart := d + 7*n - (int(rec.Start.Weekday())-int(rec.WeekStart)+7)%7
+
+		weekdays := []time.Weekday{rec.Start.Weekday()}
+		if len(rec.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, wd := range rec.ByDay {
+				weekdays = append(weekdays, wd.Weekday)
+			}
+		}
+
+		for _, wd := range weekdays {
+			t := time.Date(y, m, weekStart+(int(wd)-int(rec.WeekStart)+7)%7, hh, mm, ss, ns, loc)
+			if rec.matchesMonth(t.Month()) {
+				occurrences = append(occurrences, t)
+			}
+		}
+	case Monthly:
+		first := time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, loc)
+		if !rec.matchesMonth(first.Month()) {
+			break
+		}
+		for _, day := range rec.monthDays(first.Year(), first.Month(), d) {
+			occurrences = append(occurrences, time.Date(first.Year(), first.Month(), day, hh, mm, ss, ns, loc))
+		}
+	case Yearly:
+		year := y + n
+
+		// weekdays without a month count their position within the whole year
+		if len(rec.ByDay) > 0 && len(rec.ByMonth) == 0 && len(rec.ByMonthDay) == 0 {
+			for t := time.Date(year, time.January, 1, hh, mm, ss, ns, loc); t.Year() == year; t = t.AddDate(0, 0, 1) {
+				for _, wd := range rec.ByDay {
+					if wd.matchesInYear(t) {
+						occurrences = append(occurrences, t)
+						break
+					}
+				}
+			}
+			break
+		}
+
+		months := rec.ByMonth
+		if len(months) == 0 && len(rec.ByMonthDay) > 0 {
+			months = []time.Month{time.January, time.February, time.March, time.April, time.May, time.June,
+				time.July, time.August, time.September, time.October, time.November, time.December}
+		}
+		if len(months) == 0 {
+			months = []time.Month{m}
+		}
+
+		for _, month := range months {
+			for _, day := range rec.monthDays(year, month, d) {
+				occurrences = append(occurrences, time.Date(year, month, day, hh, mm, ss, ns, loc))
+			}
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Before(occurrences[j])
+	})
+
+	return occurrences
+}
+
+// matchesMonth reports whether the month is selected by ByMonth.
+func (rec *Recurrence) matchesMonth(month time.Month) bool {
+	if len(rec.ByMonth) == 0 {
+		return true
+	}
+
+	for _, m := range rec.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+
+	return false
+}
+
+// monthDays returns the days of the given month selected by ByDay and ByMonthDay, or
+// just defaultDay when neither is set. When both are set, a day must satisfy both.
+func (rec *Recurrence) monthDays(year int, month time.Month, defaultDay int) []int {
+	last := daysIn(year, month)
+
+	if len(rec.ByDay) == 0 && len(rec.ByMonthDay) == 0 {
+		if defaultDay > last {
+			return nil
+		}
+		return []int{defaultDay}
+	}
+
+	var days []int
+
+	for day := 1; day <= last; day++ {
+		t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		if rec.matchesDay(t) {
+			days = append(days, day)
+		}
+	}
+
+	return days
+}
+
+// matchesDay reports whether the day of t is selected by ByDay and ByMonthDay.
+func (rec *Recurrence) matchesDay(t time.Time) bool {
+	if len(rec.ByDay) > 0 {
+		found := false
+		for _, wd := range rec.ByDay {
+			if wd.matches(t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(rec.ByMonthDay) > 0 {
+		last := daysIn(t.Year(), t.Month())
+		found := false
+		for _, md := range rec.ByMonthDay {
+			if md < 0 {
+				md += last + 1
+			}
+			if md == t.Day() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matches reports whether t falls on the weekday, honoring its position in the month.
+func (wd WeekdayNum) matches(t time.Time) bool {
+	if t.Weekday() != wd.Weekday {
+		return false
+	}
+
+	switch {
+	case wd.N > 0:
+		return (t.Day()-1)/7+1 == wd.N
+	case wd.N < 0:
+		return (daysIn(t.Year(), t.Month())-t.Day())/7+1 == -wd.N
+	}
+
+	return true
+}
+
+// matchesInYear reports whether t falls on the weekday, honoring its position in the year.
+func (wd WeekdayNum) matchesInYear(t time.Time) bool {
+	if t.Weekday() != wd.Weekday {
+		return false
+	}
+
+	switch {
+	case wd.N > 0:
+		return (t.YearDay()-1)/7+1 == wd.N
+	case wd.N < 0:
+		last := time.Date(t.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		return (last-t.YearDay())/7+1 == -wd.N
+	}
+
+	return true
+}
+
+// duration returns the length of one unit of the frequency, if it has a fixed length.
+func (f Frequency) duration() time.Duration {
+	switch f {
+	case Secondly:
+		return time.Second
+	case Minutely:
+		return time.Minute
+	case Hourly:
+		return time.Hour
+	case Daily:
+		return 24 * time.Hour
+	case Weekly:
+		return 7 * 24 * time.Hour
+	}
+
+	return 0
+}
+
+// daysIn returns the number of days in the given month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// ParseRecurrence takes an English description of a repeating schedule - such as "every weekday at 8:30am"
+// or "on the 1st and 15th of every month" - and the time it should start from.
+// It returns the schedule as a Recurrence, or an error if the input cannot be recognized.
+// Of Parse's options, only WeekStart applies.
+func ParseRecurrence(s string, ref time.Time, opts ...Option) (*Recurrence, error) {
+	r := &recurrenceResult{}
+	steps, pos := matchFormats(lex(s), skipSpace(s, 0), bind(allRecurrenceFormats, r))
+
+	if pos < len(s) {
+		return nil, fmt.Errorf(`strtotime: Unrecognizable input: "%v"`, strings.TrimSpace(s[pos:]))
+	}
+
+	if err := apply(nil, steps); err != nil {
+		return nil, err
+	}
+
+	if r.freq == nil {
+		return nil, fmt.Errorf(`strtotime: "%v" does not describe a recurrence`, s)
+	}
+
+	rec := &Recurrence{
+		Start:      ref,
+		Freq:       *r.freq,
+		Interval:   r.interval,
+		ByDay:      r.byDay,
+		ByMonthDay: r.byMonthDay,
+		WeekStart:  newOptions(opts).weekStart,
+	}
+
+	if rec.Interval < 1 {
+		rec.Interval = 1
+	}
+
+	y, m, d := ref.Date()
+
+	if r.clock != nil {
+		rec.Start = time.Date(y, m, d, *r.clock.h, *r.clock.i, *r.clock.s, 0, ref.Location())
+	} else if len(r.byDay) > 0 || len(r.byMonthDay) > 0 {
+		rec.Start = time.Date(y, m, d, 0, 0, 0, 0, ref.Location())
+	}
+
+	// start on the first occurrence, so that "every other friday" counts from the coming friday
+	// rather than from the week of ref
+	first := *rec
+	first.Interval = 1
+	if start := first.next(rec.Start.Add(-time.Nanosecond), 0); !start.IsZero() {
+		rec.Start = start
+	}
+
+	return rec, nil
+}
+
+// recurrenceResult holds the parts of a recurrence as they are recognized
+type recurrenceResult struct {
+	freq       *Frequency
+	interval   int
+	byDay      []WeekdayNum
+	byMonthDay []int
+
+	// time of day
+	clock *result
+}
+
+func (r *recurrenceResult) frequency(f Frequency, interval int) error {
+	if r.freq != nil && *r.freq != f {
+		return fmt.Errorf("strtotime: The string contains two conflicting frequencies")
+	}
+
+	r.freq = &f
+
+	if interval > 0 {
+		r.interval = interval
+	}
+
+	return nil
+}
+
+func (r *recurrenceResult) timeOfDay(s string) error {
+	if r.clock != nil {
+		return fmt.Errorf("strtotime: The string contains two conflicting hours")
+	}
+
+	// a bare number, as in "every day at 9", is an hour
+	if h, err := strconv.Atoi(s); err == nil {
+		if h > 23 {
+			return fmt.Errorf(`strtotime: "%v" is not a time of day`, s)
+		}
+		clock := &result{}
+		if err := clock.time(h, 0, 0, 0); err != nil {
+			return err
+		}
+		r.clock = clock
+		return nil
+	}
+
+	clock, err := parse(s)
+
+	if err != nil {
+		return err
+	}
+
+	// 24:00 is the start of the next day, which a time of day can't be
+	if clock.h == nil || *clock.h > 23 || clock.dates > 0 {
+		return fmt.Errorf(`strtotime: "%v" is not a time of day`, s)
+	}
+
+	r.clock = clock
+	return nil
+}
+
+// recurrenceFormat is a way of writing part of a recurrence, which its rule matches
+type recurrenceFormat struct {
+	rule     rule
+	name     string
+	callback func(r *recurrenceResult, inputs ...string) error
+}
+
+// bind returns the formats as formats of Parse's, whose callbacks write to r
+func bind(formats []recurrenceFormat, r *recurrenceResult) []format {
+	var bound []format
+
+	for _, f := range formats {
+		callback := f.callback
+		bound = append(bound, format{f.rule, f.name, func(_ *result, inputs ...string) error {
+			return callback(r, inputs...)
+		}})
+	}
+
+	return bound
+}
+
+// The pieces of the grammar of recurrences: units of time, times of day, and the order of a weekday in its month.
+var (
+	recurUnit  = group(word(plurals("sec", "second", "min", "minute", "hour", "day", "week", "fortnight", "month", "quarter", "year")...))
+	recurClock = alt(
+		word("noon", "midnight"),
+		seq(digits(1, 2, nil), char(":."), digits(2, 2, nil), opt(seq(char(":."), digits(2, 2, nil))), opt(seq(spaceOpt, word("am", "pm")))),
+		seq(digits(1, 2, nil), spaceOpt, word("am", "pm")),
+	)
+	recurOrder = group(alt(word("first", "second", "third", "fourth", "fifth", "last"), seq(digits(1, anyLength, nil), daySuffix)))
+)
+
+// lookupFrequency converts a unit, such as "fortnight", to a frequency and the interval it implies
+func lookupFrequency(unit string) (Frequency, int) {
+	unit = strings.TrimSuffix(strings.ToLower(unit), "s")
+
+	switch unit {
+	case "sec", "second", "secondly":
+		return Secondly, 1
+	case "min", "minute", "minutely":
+		return Minutely, 1
+	case "hour", "hourly":
+		return Hourly, 1
+	case "day", "daily":
+		return Daily, 1
+	case "week", "weekly":
+		return Weekly, 1
+	case "fortnight", "fortnightly":
+		return Weekly, 2
+	case "month", "monthly":
+		return Monthly, 1
+	case "quarter", "quarterly":
+		return Monthly, 3
+	}
+
+	return Yearly, 1
+}
+
+// allRecurrenceFormats are the formats ParseRecurrence runs, whose rules are built once
+var allRecurrenceFormats = recurrenceFormats()
+
+func recurrenceFormats() []recurrenceFormat {
+
+	every := recurrenceFormat{
+		rule: seq(word("every", "each"), wordEnd()),
+		name: "every",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			return nil
+		},
+	}
+
+	other := recurrenceFormat{
+		rule: seq(word("other"), wordEnd()),
+		name: "other",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			r.interval = 2
+			return nil
+		},
+	}
+
+	ofMonth := recurrenceFormat{
+		rule: seq(word("of"), space, word("the", "every", "each"), space, word("month"), wordEnd()),
+		name: "ofmonth",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			return r.frequency(Monthly, 0)
+		},
+	}
+
+	atTime := recurrenceFormat{
+		rule: seq(word("at"), space, group(alt(recurClock, digits(1, 2, nil))), wordEnd()),
+		name: "attime",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			return r.timeOfDay(inputs[0])
+		},
+	}
+
+	timeOfDay := recurrenceFormat{
+		rule: seq(group(recurClock), wordEnd()),
+		name: "time",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			return r.timeOfDay(inputs[0])
+		},
+	}
+
+	ordinalWeekday := recurrenceFormat{
+		rule: seq(recurOrder, space, group(word(concat(dayFull, dayAbbr)...)), wordEnd()),
+		name: "ordinalweekday",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			n, _ := lookupRelative(strings.ToLower(inputs[0]))
+
+			if n == 0 {
+				var err error
+				n, err = strconv.Atoi(strings.TrimRight(inputs[0], "stndrhSTNDRH"))
+				if err != nil || n < 1 || n > 5 {
+					return fmt.Errorf("strtotime: A month has no %v %v", inputs[0], inputs[1])
+				}
+			}
+
+			r.byDay = append(r.byDay, WeekdayNum{time.Weekday(lookupWeekday(inputs[1], 0)), n})
+
+			if r.freq == nil {
+				return r.frequency(Monthly, 0)
+			}
+			return nil
+		},
+	}
+
+	lastDay := recurrenceFormat{
+		rule: seq(word("last"), space, word("day"), wordEnd()),
+		name: "lastday",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			r.byMonthDay = append(r.byMonthDay, -1)
+			return nil
+		},
+	}
+
+	monthDay := recurrenceFormat{
+		rule: seq(group(digits(1, 2, between(1, 31))), daySuffix, wordEnd()),
+		name: "monthday",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			day, err := strconv.Atoi(inputs[0])
+			if err != nil {
+				return err
+			}
+
+			r.byMonthDay = append(r.byMonthDay, day)
+			return nil
+		},
+	}
+
+	interval := recurrenceFormat{
+		rule: seq(group(digits(1, anyLength, nil)), spaceOpt, recurUnit, wordEnd()),
+		name: "interval",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			n, err := strconv.Atoi(inputs[0])
+			if err != nil {
+				return err
+			}
+
+			if n < 1 {
+				return fmt.Errorf("strtotime: A recurrence interval must be positive")
+			}
+
+			freq, multiplier := lookupFrequency(inputs[1])
+			return r.frequency(freq, n*multiplier)
+		},
+	}
+
+	unit := recurrenceFormat{
+		rule: seq(recurUnit, wordEnd()),
+		name: "unit",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			freq, multiplier := lookupFrequency(inputs[0])
+
+			if multiplier > 1 {
+				return r.frequency(freq, multiplier)
+			}
+			return r.frequency(freq, 0)
+		},
+	}
+
+	adverb := recurrenceFormat{
+		rule: seq(group(word("hourly", "daily", "weekly", "fortnightly", "monthly", "quarterly", "yearly", "annually")), wordEnd()),
+		name: "adverb",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			freq, multiplier := lookupFrequency(inputs[0])
+			return r.frequency(freq, multiplier)
+		},
+	}
+
+	weekdays := recurrenceFormat{
+		rule: seq(group(word(plurals("weekday", "weekend")...)), wordEnd()),
+		name: "weekdays | weekends",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			days := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+			if strings.HasPrefix(strings.ToLower(inputs[0]), "weekend") {
+				days = []time.Weekday{time.Saturday, time.Sunday}
+			}
+
+			for _, wd := range days {
+				r.byDay = append(r.byDay, WeekdayNum{Weekday: wd})
+			}
+
+			if r.freq == nil {
+				return r.frequency(Weekly, 0)
+			}
+			return nil
+		},
+	}
+
+	dayText := recurrenceFormat{
+		rule: seq(group(word(plurals(concat(dayFull, dayAbbr)...)...)), wordEnd()),
+		name: "daytext",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			r.byDay = append(r.byDay, WeekdayNum{Weekday: time.Weekday(lookupWeekday(strings.TrimSuffix(strings.ToLower(inputs[0]), "s"), 0))})
+
+			if r.freq == nil {
+				return r.frequency(Weekly, 0)
+			}
+			return nil
+		},
+	}
+
+	connective := recurrenceFormat{
+		rule: alt(run(" ,", 1), seq(word("and", "on", "the", "of", "in"), wordEnd())),
+		name: "connective",
+		callback: func(r *recurrenceResult, inputs ...string) error {
+			return nil
+		},
+	}
+
+	return []recurrenceFormat{
+		every,
+		other,
+		ofMonth,
+		atTime,
+		timeOfDay,
+		ordinalWeekday,
+		lastDay,
+		monthDay,
+		interval,
+		adverb,
+		unit,
+		weekdays,
+		dayText,
+		connective,
+	}
+}
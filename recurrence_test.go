@@ -0,0 +1,69 @@
+package strtotime
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d, h, i int) time.Time {
+	return time.Date(y, m, d, h, i, 0, 0, time.UTC)
+}
+
+var recurrenceTests = []struct {
+	in  string
+	out []time.Time
+}{
+	{"every day at 9", []time.Time{date(2015, 7, 6, 9, 0), date(2015, 7, 7, 9, 0), date(2015, 7, 8, 9, 0)}},
+	{"every other Friday", []time.Time{date(2015, 7, 10, 0, 0), date(2015, 7, 24, 0, 0), date(2015, 8, 7, 0, 0)}},
+	{"every weekday at 8:30am", []time.Time{date(2015, 7, 6, 8, 30), date(2015, 7, 7, 8, 30), date(2015, 7, 8, 8, 30)}},
+	{"every 15 minutes", []time.Time{date(2015, 7, 5, 13, 15), date(2015, 7, 5, 13, 30), date(2015, 7, 5, 13, 45)}},
+	{"on the 1st and 15th of every month", []time.Time{date(2015, 7, 15, 0, 0), date(2015, 8, 1, 0, 0), date(2015, 8, 15, 0, 0)}},
+	{"every last friday of the month", []time.Time{date(2015, 7, 31, 0, 0), date(2015, 8, 28, 0, 0), date(2015, 9, 25, 0, 0)}},
+	{"every Monday at 9am", []time.Time{date(2015, 7, 6, 9, 0), date(2015, 7, 13, 9, 0), date(2015, 7, 20, 9, 0)}},
+	{"every 2 weeks", []time.Time{date(2015, 7, 19, 13, 0), date(2015, 8, 2, 13, 0), date(2015, 8, 16, 13, 0)}},
+	{"every 2nd tuesday", []time.Time{date(2015, 7, 14, 0, 0), date(2015, 8, 11, 0, 0), date(2015, 9, 8, 0, 0)}},
+	{"every monday and thursday at noon", []time.Time{date(2015, 7, 6, 12, 0), date(2015, 7, 9, 12, 0), date(2015, 7, 13, 12, 0)}},
+	{"monthly on the last day", []time.Time{date(2015, 7, 31, 0, 0), date(2015, 8, 31, 0, 0), date(2015, 9, 30, 0, 0)}},
+	{"every year", []time.Time{date(2016, 7, 5, 13, 0), date(2017, 7, 5, 13, 0), date(2018, 7, 5, 13, 0)}},
+	{"weekends at 10:30", []time.Time{date(2015, 7, 11, 10, 30), date(2015, 7, 12, 10, 30), date(2015, 7, 18, 10, 30)}},
+	{"Mondays, Wednesdays at 7pm", []time.Time{date(2015, 7, 6, 19, 0), date(2015, 7, 8, 19, 0), date(2015, 7, 13, 19, 0)}},
+}
+
+func TestParseRecurrence(t *testing.T) {
+	for _, tt := range recurrenceTests {
+		t.Run(tt.in, func(t *testing.T) {
+			rec, err := ParseRecurrence(tt.in, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			after := now
+			for _, want := range tt.out {
+				after = rec.Next(after)
+				if !after.Equal(want) {
+					t.Fatalf("Occurrence should have been %v, but it was %v", want, after)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	for _, in := range []string{"every", "sometimes", "every day at 9 at 10", "every day of every month", "every day at 24", "every day at 24:00", "every 0th tuesday", "every 9th tuesday", "every 10th friday"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseRecurrence(in, now); err == nil {
+				t.Errorf("%q should not have been recognized", in)
+			}
+		})
+	}
+}
+
+func TestRecurrenceNextFarAhead(t *testing.T) {
+	rec, err := ParseRecurrence("every 15 minutes", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := date(2025, 1, 1, 0, 7)
+	if got, want := rec.Next(after), date(2025, 1, 1, 0, 15); !got.Equal(want) {
+		t.Errorf("Occurrence should have been %v, but it was %v", want, got)
+	}
+}
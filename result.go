@@ -18,6 +18,7 @@ type result struct {
 	h *int
 	i *int
 	s *int
+	// fraction of the second, in nanoseconds, as is rf
 	f *int
 
 	// relative shifts
@@ -33,19 +34,62 @@ type result struct {
 	weekday         *int
 	weekdayBehavior int
 
+	// the day weeks start on, and whether "end of week" asked for their last day
+	weekStart time.Weekday
+	endOfWeek bool
+
+	// the occurrence dates that leave out part of themselves refer to, and where shifts
+	// by months land on days their month doesn't have
+	prefer   Preference
+	overflow Overflow
+
 	// first or last day of month
 	// 0 none, 1 first, -1 last
 	firstOrLastDayOfMonth int
 
+	// month relative to the current one, as in "next march"
+	// 0 none, 1 next, -1 last
+	relativeMonth int
+
 	// timezone correction in minutes
 	z *int
 
+	// the parts of a date the input gave, and the units it shifted by
+	given component
+	units units
+
 	// counters
 	dates int
 	times int
 	zones int
 }
 
+// component is a set of the parts of a date
+type component int
+
+const (
+	givenYear component = 1 << iota
+	givenMonth
+	givenDay
+	givenWeekday
+	givenHour
+	givenMinute
+	givenSecond
+	givenFraction
+	givenZone
+
+	givenDate  = givenYear | givenMonth | givenDay
+	givenClock = givenHour | givenMinute | givenSecond
+)
+
+// units is a set of frequencies
+type units int
+
+// shifted records a relative shift by the unit
+func (r *result) shifted(unit Frequency) {
+	r.units |= 1 << uint(unit)
+}
+
 func (r *result) ymd(y, m, d int) error {
 	if r.dates > 0 {
 		return fmt.Errorf("strtotime: The string contains two conflicting date/months")
@@ -82,19 +126,59 @@ func (r *result) resetTime() error {
 	return nil
 }
 
+// resetDayTime resets the time for a weekday, which is read from midnight, unless the input gave a time before it,
+// as in "3pm friday"
+func (r *result) resetDayTime() {
+	if r.times == 0 {
+		r.resetTime()
+	}
+}
+
 func (r *result) zone(minutes int) error {
 	if r.zones > 0 {
 		return fmt.Errorf("strtotime: The string contains two conflicting time zones")
 
 	}
 	r.zones++
+	r.given |= givenZone
 	r.z = pointer(minutes)
 	return nil
 }
 
+// clone returns a copy of r that shares none of its fields with it, since toDate changes them
+func (r *result) clone() *result {
+	c := *r
+
+	for _, p := range []**int{&c.y, &c.m, &c.d, &c.h, &c.i, &c.s, &c.f, &c.weekday, &c.z} {
+		if *p != nil {
+			*p = pointer(**p)
+		}
+	}
+
+	return &c
+}
+
+// toDate is resolve for times known to be in range, which returns the zero Time for the others
 func (r *result) toDate(re int64) time.Time {
+	t, _ := r.resolve(re)
+	return t
+}
+
+// resolve returns the time r refers to, relative to the unix timestamp re. It fails with a *RangeError
+// when the time is too far from 1970 for a time.Time to hold.
+func (r *result) resolve(re int64) (time.Time, error) {
+	return r.resolveAt(time.Unix(re, 0))
+}
 
-	relativeTo := time.Unix(re, 0).UTC()
+// resolveAt is resolve relative to a time, down to its nanoseconds. The time is read, and the result
+// returned, in UTC.
+func (r *result) resolveAt(ref time.Time) (time.Time, error) {
+
+	if t, ok := r.preferred(ref); ok {
+		return t, nil
+	}
+
+	relativeTo := ref.UTC()
 
 	if r.dates > 0 && r.times <= 0 {
 		r.h = pointer(0)
@@ -135,10 +219,21 @@ func (r *result) toDate(re int64) time.Time {
 	}
 
 	if r.f == nil {
-		f := relativeTo.Nanosecond() / 1000000
+		f := relativeTo.Nanosecond()
 		r.f = &f
 	}
 
+	// "next march" is the first march after the current month, "last march" the last one before it
+	currentMonth := lookupMonth(relativeTo.Month().String())
+
+	if r.relativeMonth > 0 && *r.m <= currentMonth {
+		*r.y++
+	}
+
+	if r.relativeMonth < 0 && *r.m >= currentMonth {
+		*r.y--
+	}
+
 	// adjust special early
 	switch r.firstOrLastDayOfMonth {
 	case 1:
@@ -149,51 +244,58 @@ func (r *result) toDate(re int64) time.Time {
 		break
 	}
 
-	if r.weekday != nil {
+	if r.weekdayBehavior == 2 {
+		// weekdays within the week, counting from the day it starts on,
+		// which is the day "this week" and "start of week" refer to
+		dow := (int(relativeTo.Weekday()) - int(r.weekStart) + 7) % 7
+		target := 0
 
-		var dow = lookupWeekday(relativeTo.Weekday().String(), 1)
+		if r.weekday != nil {
+			target = ((*r.weekday-int(r.weekStart))%7 + 7) % 7
+		} else if r.endOfWeek {
+			target = 6
+		}
 
-		if r.weekdayBehavior == 2 {
-			// To make "r week" work, where the current day of week is a "sunday"
-			if dow == 0 && *r.weekday != 0 {
-				*r.weekday = -6
-			}
+		*r.d += target - dow
+	} else if r.weekday != nil {
 
-			// To make "sunday r week" work, where the current day of week is not a "sunday"
-			if *r.weekday == 0 && dow != 0 {
-				*r.weekday = 7
-			}
+		var dow = lookupWeekday(relativeTo.Weekday().String(), 1)
+		var diff = *r.weekday - dow
 
-			*r.d -= dow
-			*r.d += *r.weekday
-		} else {
-			var diff = *r.weekday - dow
+		//TODO: Fix this madness
+		if (r.rd < 0 && diff < 0) || (r.rd >= 0 && diff <= -r.weekdayBehavior) {
+			diff += 7
+		}
 
+		if *r.weekday >= 0 {
+			*r.d += diff
+		} else {
 			//TODO: Fix this madness
-			if (r.rd < 0 && diff < 0) || (r.rd >= 0 && diff <= -r.weekdayBehavior) {
-				diff += 7
-			}
+			*r.d -= int((7 - (math.Abs(float64(*r.weekday)) - float64(dow))))
+		}
+
+		r.weekday = nil
+	}
 
-			if *r.weekday >= 0 {
-				*r.d += diff
-			} else {
-				//TODO: Fix this madness
-				*r.d -= int((7 - (math.Abs(float64(*r.weekday)) - float64(dow))))
+	// shifts by months and years that land past the end of the month stay on its last day, unless
+	// the day is the first or last of the month anyway
+	if r.overflow == OverflowClamp && (r.ry != 0 || r.rm != 0) && r.firstOrLastDayOfMonth == 0 {
+		y, m, d := time.Date(*r.y, lookupNumberToMonth(*r.m), *r.d, 0, 0, 0, 0, time.UTC).Date()
+		*r.y, *r.m, *r.d = y, int(m)-1, d
+
+		if addShift(r.y, r.ry, 1) && addShift(r.m, r.rm, 1) {
+			if last := daysIn(*r.y, lookupNumberToMonth(*r.m)); *r.d > last {
+				*r.d = last
 			}
 
-			r.weekday = nil
+			r.ry = 0
+			r.rm = 0
 		}
 	}
 
 	// adjust relative
-	*r.y += r.ry
-	*r.m += r.rm
-	*r.d += r.rd
-
-	*r.h += r.rh
-	*r.i += r.ri
-	*r.s += r.rs
-	*r.f += r.rf
+	ok := addShift(r.y, r.ry, 1) && addShift(r.m, r.rm, 1) && addShift(r.d, r.rd, 1) &&
+		addShift(r.h, r.rh, 1) && addShift(r.i, r.ri, 1) && addShift(r.s, r.rs, 1) && addShift(r.f, r.rf, 1)
 
 	r.ry = 0
 	r.rm = 0
@@ -225,5 +327,103 @@ func (r *result) toDate(re int64) time.Time {
 		*r.i += *r.z
 	}
 
-	return time.Date(*r.y, lookupNumberToMonth(*r.m), *r.d, *r.h, *r.i, *r.s, *r.f, time.UTC)
+	// the whole seconds shifts by nanoseconds add up to count as seconds, which inRange checks
+	ok = ok && addShift(r.s, *r.f/int(time.Second), 1)
+	*r.f %= int(time.Second)
+
+	if !ok || !inRange(*r.y, *r.m, *r.d, *r.h, *r.i, *r.s) {
+		return time.Time{}, &RangeError{}
+	}
+
+	return time.Date(*r.y, lookupNumberToMonth(*r.m), *r.d, *r.h, *r.i, *r.s, *r.f, time.UTC), nil
+}
+
+// preferred resolves r to the occurrence its preference picks among those around ref, if it has a
+// preference and leaves out part of itself without saying anything relative, as "March 3" does
+func (r *result) preferred(ref time.Time) (time.Time, bool) {
+	if r.prefer == 0 || r.units != 0 || r.relativeMonth != 0 || r.weekdayBehavior == 2 || r.endOfWeek ||
+		r.ry != 0 || r.rm != 0 || r.rd != 0 || r.rh != 0 || r.ri != 0 || r.rs != 0 || r.rf != 0 {
+		return time.Time{}, false
+	}
+
+	relativeTo := ref.UTC()
+
+	// the reference time n occurrences away, whose year, month or day fills in what r leaves out, and
+	// how far to look for an occurrence: February 29th comes every 4 years, and the 31st skips a month
+	var around func(n int) time.Time
+	span := 1
+
+	switch {
+	case r.y != nil:
+		return time.Time{}, false
+	case r.m != nil:
+		around = func(n int) time.Time { return addMonths(relativeTo, 12*n) }
+		span = 4
+	case r.d != nil || r.firstOrLastDayOfMonth != 0:
+		around = func(n int) time.Time { return addMonths(relativeTo, n) }
+		span = 2
+	case r.weekday != nil:
+		around = func(n int) time.Time { return relativeTo.AddDate(0, 0, 7*n) }
+	case r.h != nil:
+		around = func(n int) time.Time { return relativeTo.AddDate(0, 0, n) }
+	default:
+		return time.Time{}, false
+	}
+
+	var picked time.Time
+
+	for n := -span; n <= span; n++ {
+		ref := around(n)
+
+		// the days a month doesn't have don't occur in it
+		if r.d != nil && r.firstOrLastDayOfMonth == 0 {
+			m := ref.Month()
+			if r.m != nil {
+				m = lookupNumberToMonth(*r.m)
+			}
+			if *r.d < 1 || *r.d > daysIn(ref.Year(), m) {
+				continue
+			}
+		}
+
+		c := r.clone()
+		c.prefer = 0
+		t, _ := c.resolveAt(ref)
+
+		switch {
+		case picked.IsZero():
+		case r.prefer == PreferFuture && picked.Before(relativeTo):
+		case r.prefer == PreferPast && !t.After(relativeTo):
+		case r.prefer == PreferNearest && distance(t, relativeTo) < distance(picked, relativeTo):
+		case r.prefer == PreferNearest && distance(t, relativeTo) == distance(picked, relativeTo) && picked.Before(relativeTo):
+		default:
+			continue
+		}
+
+		picked = t
+	}
+
+	return picked, !picked.IsZero()
+}
+
+// distance returns how far apart a and b are
+func distance(a, b time.Time) time.Duration {
+	if a.Before(b) {
+		return b.Sub(a)
+	}
+
+	return a.Sub(b)
+}
+
+// addMonths adds n months to t, keeping its day within the month it lands in, so that a month after January
+// 31st is the last day of February
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	last := daysIn(y, m+time.Month(n))
+
+	if d > last {
+		d = last
+	}
+
+	return time.Date(y, m+time.Month(n), d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 }
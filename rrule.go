@@ -0,0 +1,238 @@
+package strtotime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rruleFrequencies holds the RFC 5545 name of each Frequency
+var rruleFrequencies = []string{"SECONDLY", "MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+// rruleDays holds the RFC 5545 name of each time.Weekday
+var rruleDays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRRule takes an RFC 5545 recurrence rule - such as "FREQ=MONTHLY;BYDAY=-1FR" - and the DTSTART it applies to.
+// It returns the rule as a Recurrence, or an error if the rule is malformed or uses one of the parts that
+// are not supported: BYSECOND, BYMINUTE, BYHOUR, BYYEARDAY, BYWEEKNO and BYSETPOS.
+// An UNTIL without a time of day includes the whole of that day.
+func ParseRRule(rule string, dtstart time.Time) (*Recurrence, error) {
+	rule = strings.TrimSpace(rule)
+
+	if strings.HasPrefix(strings.ToUpper(rule), "RRULE:") {
+		rule = rule[len("RRULE:"):]
+	}
+
+	rec := &Recurrence{
+		Start:     dtstart,
+		Interval:  1,
+		WeekStart: time.Monday,
+	}
+
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(rule, ";") {
+		nameValue := strings.SplitN(part, "=", 2)
+
+		if len(nameValue) != 2 {
+			return nil, fmt.Errorf(`strtotime: Malformed RRULE part: "%v"`, part)
+		}
+
+		name, value := strings.ToUpper(nameValue[0]), strings.ToUpper(nameValue[1])
+
+		if seen[name] {
+			return nil, fmt.Errorf("strtotime: The RRULE contains two %v parts", name)
+		}
+		seen[name] = true
+
+		var err error
+
+		switch name {
+		case "FREQ":
+			rec.Freq, err = lookupRRuleFrequency(value)
+		case "INTERVAL":
+			rec.Interval, err = rruleInt(name, value, 1, 0)
+		case "COUNT":
+			rec.Count, err = rruleInt(name, value, 1, 0)
+		case "UNTIL":
+			rec.Until, err = parseRRuleUntil(value, dtstart.Location())
+		case "WKST":
+			rec.WeekStart, err = lookupRRuleDay(value)
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				wd, err := parseRRuleWeekdayNum(v)
+				if err != nil {
+					return nil, err
+				}
+				rec.ByDay = append(rec.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				md, err := rruleInt(name, v, -31, 31)
+				if err != nil {
+					return nil, err
+				}
+				if md == 0 {
+					return nil, fmt.Errorf(`strtotime: Invalid BYMONTHDAY value: "%v"`, v)
+				}
+				rec.ByMonthDay = append(rec.ByMonthDay, md)
+			}
+		case "BYMONTH":
+			for _, v := range strings.Split(value, ",") {
+				m, err := rruleInt(name, v, 1, 12)
+				if err != nil {
+					return nil, err
+				}
+				rec.ByMonth = append(rec.ByMonth, time.Month(m))
+			}
+		case "BYSECOND", "BYMINUTE", "BYHOUR", "BYYEARDAY", "BYWEEKNO", "BYSETPOS":
+			err = fmt.Errorf("strtotime: Unsupported RRULE part: %v", name)
+		default:
+			err = fmt.Errorf("strtotime: Unknown RRULE part: %v", name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !seen["FREQ"] {
+		return nil, fmt.Errorf("strtotime: The RRULE has no FREQ")
+	}
+
+	if seen["COUNT"] && seen["UNTIL"] {
+		return nil, fmt.Errorf("strtotime: The RRULE contains both COUNT and UNTIL")
+	}
+
+	return rec, nil
+}
+
+// RRule returns the recurrence as an RFC 5545 recurrence rule, such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR".
+// Start is not part of the rule, and should be stored alongside it as its DTSTART.
+func (rec *Recurrence) RRule() string {
+	parts := []string{"FREQ=" + rruleFrequencies[rec.Freq]}
+
+	if rec.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(rec.Interval))
+	}
+
+	if rec.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(rec.Count))
+	}
+
+	if !rec.Until.IsZero() {
+		parts = append(parts, "UNTIL="+rec.Until.UTC().Format("20060102T150405Z"))
+	}
+
+	if len(rec.ByMonth) > 0 {
+		months := make([]string, len(rec.ByMonth))
+		for i, m := range rec.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+
+	if len(rec.ByMonthDay) > 0 {
+		days := make([]string, len(rec.ByMonthDay))
+		for i, md := range rec.ByMonthDay {
+			days[i] = strconv.Itoa(md)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if len(rec.ByDay) > 0 {
+		days := make([]string, len(rec.ByDay))
+		for i, wd := range rec.ByDay {
+			if wd.N != 0 {
+				days[i] = strconv.Itoa(wd.N)
+			}
+			days[i] += rruleDays[wd.Weekday]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if rec.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+rruleDays[rec.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func lookupRRuleFrequency(freq string) (Frequency, error) {
+	for f, name := range rruleFrequencies {
+		if name == freq {
+			return Frequency(f), nil
+		}
+	}
+
+	return 0, fmt.Errorf(`strtotime: Invalid FREQ value: "%v"`, freq)
+}
+
+func lookupRRuleDay(day string) (time.Weekday, error) {
+	for wd, name := range rruleDays {
+		if name == day {
+			return time.Weekday(wd), nil
+		}
+	}
+
+	return 0, fmt.Errorf(`strtotime: Invalid weekday: "%v"`, day)
+}
+
+// parseRRuleWeekdayNum converts a BYDAY value, such as "-1FR", to a WeekdayNum
+func parseRRuleWeekdayNum(value string) (WeekdayNum, error) {
+	match := regexp.MustCompile(`^([+-]?[0-9]{1,2})?([A-Z]{2})$`).FindStringSubmatch(value)
+
+	if match == nil {
+		return WeekdayNum{}, fmt.Errorf(`strtotime: Invalid BYDAY value: "%v"`, value)
+	}
+
+	wd, err := lookupRRuleDay(match[2])
+	if err != nil {
+		return WeekdayNum{}, err
+	}
+
+	n := 0
+
+	if len(match[1]) > 0 {
+		n, err = rruleInt("BYDAY", match[1], -53, 53)
+		if err != nil {
+			return WeekdayNum{}, err
+		}
+	}
+
+	return WeekdayNum{wd, n}, nil
+}
+
+// parseRRuleUntil converts an UNTIL value, which is either a date or a date-time, to a Time.
+// Floating date-times are taken to be in loc, the location of DTSTART.
+func parseRRuleUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("20060102", value, loc)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`strtotime: Invalid UNTIL value: "%v"`, value)
+	}
+
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// rruleInt converts the value of an RRULE part to an int within [min, max].
+// A max of zero leaves the value unbounded.
+func rruleInt(name, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+
+	if err != nil || n < min || (max != 0 && n > max) {
+		return 0, fmt.Errorf(`strtotime: Invalid %v value: "%v"`, name, value)
+	}
+
+	return n, nil
+}
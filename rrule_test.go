@@ -0,0 +1,120 @@
+package strtotime
+
+import (
+	"testing"
+	"time"
+)
+
+var rruleStart = time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+
+var rruleTests = []struct {
+	rule  string
+	start time.Time
+	out   []time.Time
+}{
+	{"FREQ=DAILY;COUNT=3", rruleStart, []time.Time{date(1997, 9, 2, 9, 0), date(1997, 9, 3, 9, 0), date(1997, 9, 4, 9, 0)}},
+	{"RRULE:FREQ=DAILY;UNTIL=19970904T000000Z", rruleStart, []time.Time{date(1997, 9, 2, 9, 0), date(1997, 9, 3, 9, 0)}},
+	{"FREQ=DAILY;UNTIL=19970904", rruleStart, []time.Time{date(1997, 9, 2, 9, 0), date(1997, 9, 3, 9, 0), date(1997, 9, 4, 9, 0)}},
+	{"FREQ=WEEKLY;INTERVAL=2;WKST=SU;BYDAY=TU,TH;COUNT=8", rruleStart, []time.Time{
+		date(1997, 9, 2, 9, 0), date(1997, 9, 4, 9, 0), date(1997, 9, 16, 9, 0), date(1997, 9, 18, 9, 0),
+		date(1997, 9, 30, 9, 0), date(1997, 10, 2, 9, 0), date(1997, 10, 14, 9, 0), date(1997, 10, 16, 9, 0),
+	}},
+	{"FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO", date(1997, 8, 5, 9, 0), []time.Time{
+		date(1997, 8, 5, 9, 0), date(1997, 8, 10, 9, 0), date(1997, 8, 19, 9, 0), date(1997, 8, 24, 9, 0),
+	}},
+	{"FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU", date(1997, 8, 5, 9, 0), []time.Time{
+		date(1997, 8, 5, 9, 0), date(1997, 8, 17, 9, 0), date(1997, 8, 19, 9, 0), date(1997, 8, 31, 9, 0),
+	}},
+	{"FREQ=MONTHLY;COUNT=6;BYDAY=-2MO", date(1997, 9, 22, 9, 0), []time.Time{
+		date(1997, 9, 22, 9, 0), date(1997, 10, 20, 9, 0), date(1997, 11, 17, 9, 0),
+		date(1997, 12, 22, 9, 0), date(1998, 1, 19, 9, 0), date(1998, 2, 16, 9, 0),
+	}},
+	{"FREQ=MONTHLY;BYMONTHDAY=-3", date(1997, 9, 28, 9, 0), []time.Time{
+		date(1997, 9, 28, 9, 0), date(1997, 10, 29, 9, 0), date(1997, 11, 28, 9, 0), date(1997, 12, 29, 9, 0),
+	}},
+	{"FREQ=YEARLY;BYDAY=20MO", date(1997, 5, 19, 9, 0), []time.Time{
+		date(1997, 5, 19, 9, 0), date(1998, 5, 18, 9, 0), date(1999, 5, 17, 9, 0),
+	}},
+	{"FREQ=YEARLY;COUNT=4;BYMONTH=6,7", date(1997, 6, 10, 9, 0), []time.Time{
+		date(1997, 6, 10, 9, 0), date(1997, 7, 10, 9, 0), date(1998, 6, 10, 9, 0), date(1998, 7, 10, 9, 0),
+	}},
+	// DTSTART is the first occurrence, even on a Tuesday
+	{"FREQ=WEEKLY;COUNT=3;BYDAY=FR", rruleStart, []time.Time{date(1997, 9, 2, 9, 0), date(1997, 9, 5, 9, 0), date(1997, 9, 12, 9, 0)}},
+	{"FREQ=MONTHLY;BYMONTH=1;BYDAY=+1SU;INTERVAL=1", date(1998, 1, 4, 9, 0), []time.Time{
+		date(1998, 1, 4, 9, 0), date(1999, 1, 3, 9, 0), date(2000, 1, 2, 9, 0),
+	}},
+}
+
+func TestParseRRule(t *testing.T) {
+	for _, tt := range rruleTests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rec, err := ParseRRule(tt.rule, tt.start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			after := tt.start.Add(-time.Second)
+			for _, want := range tt.out {
+				after = rec.Next(after)
+				if !after.Equal(want) {
+					t.Fatalf("Occurrence should have been %v, but it was %v", want, after)
+				}
+			}
+			if next := rec.Next(after); (rec.Count > 0 || !rec.Until.IsZero()) && !next.IsZero() {
+				t.Errorf("Occurrences should have ended, but got %v", next)
+			}
+		})
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	for _, rule := range []string{
+		"INTERVAL=2",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;COUNT=2;UNTIL=19970904",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYDAY=1XX",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ",
+	} {
+		t.Run(rule, func(t *testing.T) {
+			if _, err := ParseRRule(rule, rruleStart); err == nil {
+				t.Errorf("%q should not have been accepted", rule)
+			}
+		})
+	}
+}
+
+var toRRuleTests = []struct {
+	in  string
+	out string
+}{
+	{"every 2nd tuesday", "FREQ=MONTHLY;BYDAY=2TU"},
+	{"every other Friday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR"},
+	{"every weekday at 8:30am", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+	{"every 15 minutes", "FREQ=MINUTELY;INTERVAL=15"},
+	{"on the 1st and 15th of every month", "FREQ=MONTHLY;BYMONTHDAY=1,15"},
+	{"every last friday of the month", "FREQ=MONTHLY;BYDAY=-1FR"},
+}
+
+func TestRecurrenceRRule(t *testing.T) {
+	for _, tt := range toRRuleTests {
+		t.Run(tt.in, func(t *testing.T) {
+			rec, err := ParseRecurrence(tt.in, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rule := rec.RRule()
+			if rule != tt.out {
+				t.Fatalf("RRULE should have been %v, but it was %v", tt.out, rule)
+			}
+			parsed, err := ParseRRule(rule, rec.Start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a, b := rec.Next(now), parsed.Next(now); !a.Equal(b) {
+				t.Errorf("Parsed RRULE should have occurred at %v, but it was %v", a, b)
+			}
+		})
+	}
+}
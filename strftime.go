@@ -0,0 +1,449 @@
+package strtotime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// strftimeComposites holds the directives that stand for a whole pattern, as in the C locale
+var strftimeComposites = map[byte]string{
+	'c': "%a %b %e %H:%M:%S %Y",
+	'D': "%m/%d/%y",
+	'F': "%Y-%m-%d",
+	'h': "%b",
+	'r': "%I:%M:%S %p",
+	'R': "%H:%M",
+	'T': "%H:%M:%S",
+	'x': "%m/%d/%y",
+	'X': "%H:%M:%S",
+}
+
+// Strftime formats t with a C strftime pattern, such as Strftime("%Y-%m-%d %H:%M:%S %z", t), in the C locale.
+// Besides the C and POSIX directives, it supports %f for microseconds and %:z for an offset with a colon,
+// as Python and glibc do. Unknown directives are copied as they are.
+func Strftime(pattern string, t time.Time) string {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' || i == len(pattern)-1 {
+			b.WriteByte(pattern[i])
+			continue
+		}
+
+		i++
+		c := pattern[i]
+
+		if composite, ok := strftimeComposites[c]; ok {
+			b.WriteString(Strftime(composite, t))
+			continue
+		}
+
+		switch c {
+		case 'a':
+			b.WriteString(t.Format("Mon"))
+		case 'A':
+			b.WriteString(t.Weekday().String())
+		case 'b':
+			b.WriteString(t.Format("Jan"))
+		case 'B':
+			b.WriteString(t.Month().String())
+		case 'C':
+			fmt.Fprintf(&b, "%02d", t.Year()/100)
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'e':
+			fmt.Fprintf(&b, "%2d", t.Day())
+		case 'f':
+			fmt.Fprintf(&b, "%06d", t.Nanosecond()/1000)
+		case 'g':
+			year, _ := t.ISOWeek()
+			fmt.Fprintf(&b, "%02d", year%100)
+		case 'G':
+			year, _ := t.ISOWeek()
+			b.WriteString(strconv.Itoa(year))
+		case 'H':
+			fmt.Fprintf(&b, "%02d", t.Hour())
+		case 'I':
+			fmt.Fprintf(&b, "%02d", (t.Hour()+11)%12+1)
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		case 'k':
+			fmt.Fprintf(&b, "%2d", t.Hour())
+		case 'l':
+			fmt.Fprintf(&b, "%2d", (t.Hour()+11)%12+1)
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		case 'M':
+			fmt.Fprintf(&b, "%02d", t.Minute())
+		case 'n':
+			b.WriteByte('\n')
+		case 'p':
+			b.WriteString(t.Format("PM"))
+		case 'P':
+			b.WriteString(t.Format("pm"))
+		case 's':
+			b.WriteString(strconv.FormatInt(t.Unix(), 10))
+		case 'S':
+			fmt.Fprintf(&b, "%02d", t.Second())
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			b.WriteString(strconv.Itoa((int(t.Weekday())+6)%7 + 1))
+		case 'U':
+			fmt.Fprintf(&b, "%02d", (t.YearDay()+6-int(t.Weekday()))/7)
+		case 'V':
+			_, week := t.ISOWeek()
+			fmt.Fprintf(&b, "%02d", week)
+		case 'w':
+			b.WriteString(strconv.Itoa(int(t.Weekday())))
+		case 'W':
+			fmt.Fprintf(&b, "%02d", (t.YearDay()+6-(int(t.Weekday())+6)%7)/7)
+		case 'y':
+			fmt.Fprintf(&b, "%02d", t.Year()%100)
+		case 'Y':
+			b.WriteString(strconv.Itoa(t.Year()))
+		case 'z':
+			b.WriteString(t.Format("-0700"))
+		case ':':
+			if strings.HasPrefix(pattern[i:], ":z") {
+				b.WriteString(t.Format("-07:00"))
+				i++
+			} else {
+				b.WriteString("%:")
+			}
+		case 'Z':
+			name, _ := t.Zone()
+			b.WriteString(name)
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// strptimeResult gathers the fields read by Strptime
+type strptimeResult struct {
+	year, month, day     int
+	hour, minute, second int
+	nanosecond           int
+	yearDay              int
+	pm                   *bool
+	isoYear, isoWeek     int
+	weekday              int
+	zone                 *time.Location
+	unix                 *int64
+}
+
+// Strptime parses s according to a C strptime pattern, such as Strptime("%Y-%m-%d %H:%M:%S %z", s), in the C locale.
+// It understands the same directives as Strftime, except %C, %U and %W, and like Python's strptime, whitespace in
+// the pattern matches any amount of whitespace, fields left out default to January 1st 1900 at midnight, and the
+// whole input must be used. The time is in UTC, unless the input has a %z offset, whose fixed zone it keeps.
+func Strptime(pattern, s string) (time.Time, error) {
+	r := &strptimeResult{year: 1900, month: 1, day: 1}
+
+	rest, err := r.read(pattern, s)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(rest) > 0 {
+		return time.Time{}, fmt.Errorf(`strtotime: "%v" is left over after "%v"`, rest, pattern)
+	}
+
+	return r.time()
+}
+
+// read consumes the start of s matching pattern, and returns what is left of s
+func (r *strptimeResult) read(pattern, s string) (string, error) {
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case unicode.IsSpace(rune(c)):
+			s = strings.TrimLeftFunc(s, unicode.IsSpace)
+			continue
+		case c != '%' || i == len(pattern)-1:
+			if len(s) == 0 || s[0] != c {
+				return s, fmt.Errorf(`strtotime: "%v" does not match "%v"`, s, pattern[i:])
+			}
+			s = s[1:]
+			continue
+		}
+
+		i++
+		c = pattern[i]
+
+		if composite, ok := strftimeComposites[c]; ok {
+			var err error
+			if s, err = r.read(composite, s); err != nil {
+				return s, err
+			}
+			continue
+		}
+
+		// number reads an unsigned number of up to width digits, allowing leading spaces for padded fields
+		number := func(width, min, max int) (int, error) {
+			if c == 'e' || c == 'k' || c == 'l' {
+				s = strings.TrimLeft(s, " ")
+			}
+
+			j := 0
+			for j < len(s) && j < width && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+
+			if j == 0 {
+				return 0, fmt.Errorf(`strtotime: "%v" does not start with the number for %%%c`, s, c)
+			}
+
+			n, _ := strconv.Atoi(s[:j])
+			s = s[j:]
+
+			if n < min || n > max {
+				return 0, fmt.Errorf(`strtotime: %v is out of range for %%%c`, n, c)
+			}
+
+			return n, nil
+		}
+
+		var err error
+
+		switch c {
+		case 'a', 'A':
+			r.weekday, err = r.name(&s, c, func(n int) string { return time.Weekday(n).String() }, 0, 6)
+			r.weekday = (r.weekday+6)%7 + 1
+		case 'b', 'B':
+			r.month, err = r.name(&s, c, func(n int) string { return time.Month(n).String() }, 1, 12)
+		case 'd', 'e':
+			r.day, err = number(2, 1, 31)
+		case 'f':
+			j := 0
+			for j < len(s) && j < 9 && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			if j == 0 {
+				return s, fmt.Errorf(`strtotime: "%v" does not start with the number for %%f`, s)
+			}
+			r.nanosecond, _ = strconv.Atoi(s[:j] + strings.Repeat("0", 9-j))
+			s = s[j:]
+		case 'g':
+			r.isoYear, err = number(2, 0, 99)
+			r.isoYear += 2000
+			if r.isoYear > 2068 {
+				r.isoYear -= 100
+			}
+		case 'G':
+			r.isoYear, err = number(4, 0, 9999)
+		case 'H', 'k':
+			r.hour, err = number(2, 0, 23)
+		case 'I', 'l':
+			r.hour, err = number(2, 1, 12)
+			if r.pm == nil {
+				r.pm = new(bool)
+			}
+		case 'j':
+			r.yearDay, err = number(3, 1, 366)
+		case 'm':
+			r.month, err = number(2, 1, 12)
+		case 'M':
+			r.minute, err = number(2, 0, 59)
+		case 'n', 't':
+			s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		case 'p', 'P':
+			switch {
+			case len(s) >= 2 && strings.EqualFold(s[:2], "am"):
+				r.pm = new(bool)
+			case len(s) >= 2 && strings.EqualFold(s[:2], "pm"):
+				r.pm = pointerBool(true)
+			default:
+				return s, fmt.Errorf(`strtotime: "%v" does not start with AM or PM`, s)
+			}
+			s = s[2:]
+		case 's':
+			j := 0
+			if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+				j++
+			}
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			u, err := strconv.ParseInt(s[:j], 10, 64)
+			if err != nil {
+				return s, fmt.Errorf(`strtotime: "%v" does not start with a Unix timestamp`, s)
+			}
+			r.unix = &u
+			s = s[j:]
+		case 'S':
+			// 60 and 61 are leap seconds, and carry over into the next minute
+			r.second, err = number(2, 0, 61)
+		case 'u':
+			r.weekday, err = number(1, 1, 7)
+		case 'V':
+			r.isoWeek, err = number(2, 1, 53)
+		case 'w':
+			r.weekday, err = number(1, 0, 6)
+			r.weekday = (r.weekday+6)%7 + 1
+		case 'y':
+			r.year, err = number(2, 0, 99)
+			// as in POSIX, 69-99 are in the 1900s and 00-68 in the 2000s
+			r.year += 2000
+			if r.year > 2068 {
+				r.year -= 100
+			}
+		case 'Y':
+			r.year, err = number(4, 0, 9999)
+		case 'z':
+			s, err = r.offset(s)
+		case ':':
+			if !strings.HasPrefix(pattern[i:], ":z") {
+				return s, fmt.Errorf(`strtotime: %%%v is not a strptime directive`, pattern[i:i+1])
+			}
+			i++
+			s, err = r.offset(s)
+		case 'Z':
+			j := 0
+			for j < len(s) && unicode.IsLetter(rune(s[j])) {
+				j++
+			}
+			switch strings.ToUpper(s[:j]) {
+			case "UTC", "GMT", "Z":
+				if r.zone == nil {
+					r.zone = time.UTC
+				}
+			default:
+				return s, fmt.Errorf(`strtotime: "%v" is not a time zone Strptime knows`, s[:j])
+			}
+			s = s[j:]
+		case '%':
+			if len(s) == 0 || s[0] != '%' {
+				return s, fmt.Errorf(`strtotime: "%v" does not start with %%`, s)
+			}
+			s = s[1:]
+		default:
+			return s, fmt.Errorf(`strtotime: %%%c is not a strptime directive`, c)
+		}
+
+		if err != nil {
+			return s, err
+		}
+	}
+
+	return s, nil
+}
+
+// name reads the full or abbreviated English name of one of the values from min to max
+func (r *strptimeResult) name(s *string, c byte, name func(int) string, min, max int) (int, error) {
+	for n := min; n <= max; n++ {
+		full := name(n)
+
+		for _, candidate := range []string{full, full[:3]} {
+			if len(*s) >= len(candidate) && strings.EqualFold((*s)[:len(candidate)], candidate) {
+				*s = (*s)[len(candidate):]
+				return n, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf(`strtotime: "%v" does not start with a name for %%%c`, *s, c)
+}
+
+// offset reads a UTC offset such as "Z", "+05", "+0530" or "+05:30"
+func (r *strptimeResult) offset(s string) (string, error) {
+	if len(s) > 0 && (s[0] == 'Z' || s[0] == 'z') {
+		r.zone = time.UTC
+		return s[1:], nil
+	}
+
+	if len(s) < 3 || (s[0] != '+' && s[0] != '-') {
+		return s, fmt.Errorf(`strtotime: "%v" does not start with a UTC offset`, s)
+	}
+
+	digits := s[1:3]
+	j := 3
+
+	if len(s) > j && s[j] == ':' {
+		j++
+	}
+
+	if len(s) >= j+2 {
+		digits += s[j : j+2]
+		j += 2
+	}
+
+	n, err := strconv.Atoi(digits)
+
+	if err != nil || (len(digits) == 4 && n%100 >= 60) {
+		return s, fmt.Errorf(`strtotime: "%v" does not start with a UTC offset`, s)
+	}
+
+	if len(digits) == 2 {
+		n *= 100
+	}
+
+	offset := (n/100)*3600 + (n%100)*60
+
+	if s[0] == '-' {
+		offset = -offset
+	}
+
+	r.zone = time.FixedZone("", offset)
+
+	return s[j:], nil
+}
+
+// time puts the fields together
+func (r *strptimeResult) time() (time.Time, error) {
+	zone := r.zone
+	if zone == nil {
+		zone = time.UTC
+	}
+
+	if r.unix != nil {
+		return time.Unix(*r.unix, 0).In(zone), nil
+	}
+
+	hour := r.hour
+	if r.pm != nil {
+		hour %= 12
+		if *r.pm {
+			hour += 12
+		}
+	}
+
+	year, month, day := r.year, time.Month(r.month), r.day
+
+	switch {
+	case r.isoWeek > 0:
+		if r.isoYear == 0 {
+			return time.Time{}, fmt.Errorf("strtotime: A week number needs an ISO year")
+		}
+		// January 4th is always in week 1
+		jan4 := time.Date(r.isoYear, time.January, 4, 0, 0, 0, 0, time.UTC)
+		weekday := r.weekday
+		if weekday == 0 {
+			weekday = 1
+		}
+		year, month, day = jan4.AddDate(0, 0, (r.isoWeek-1)*7+weekday-(int(jan4.Weekday())+6)%7-1).Date()
+	case r.yearDay > 0:
+		if r.yearDay > 365 && daysIn(year, time.February) == 28 {
+			return time.Time{}, fmt.Errorf("strtotime: %v has no day %v", year, r.yearDay)
+		}
+		year, month, day = time.Date(year, time.January, r.yearDay, 0, 0, 0, 0, time.UTC).Date()
+	case day > daysIn(year, month):
+		return time.Time{}, fmt.Errorf("strtotime: %v %v has no day %v", month, year, day)
+	}
+
+	return time.Date(year, month, day, hour, r.minute, r.second, r.nanosecond, zone), nil
+}
+
+func pointerBool(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,97 @@
+package strtotime
+
+import (
+	"testing"
+	"time"
+)
+
+var strftimeTests = []struct {
+	pattern string
+	t       time.Time
+	out     string
+}{
+	{"%Y-%m-%d %H:%M:%S %z", phpTime, "2001-03-10 17:16:18 +0000"},
+	{"%a %A %b %B %h", phpTime, "Sat Saturday Mar March Mar"},
+	{"%c", phpTime, "Sat Mar 10 17:16:18 2001"},
+	{"%D %F %R %T %r", phpTime, "03/10/01 2001-03-10 17:16 17:16:18 05:16:18 PM"},
+	{"%x %X", phpTime, "03/10/01 17:16:18"},
+	{"%C %y %j %e %k %l %I %p %P", time.Date(2001, 1, 5, 9, 3, 0, 0, time.UTC), "20 01 005  5  9  9 09 AM am"},
+	{"%u %w %U %W %V %G %g", time.Date(2005, 1, 2, 0, 0, 0, 0, time.UTC), "7 0 01 00 53 2004 04"},
+	{"%s", phpTime, "984244578"},
+	{"%H:%M:%S.%f %:z %Z", time.Date(2004, 2, 12, 15, 19, 21, 123456789, time.FixedZone("IST", 19800)), "15:19:21.123456 +05:30 IST"},
+	{"100%% %n%t%Q %", phpTime, "100% \n\t%Q %"},
+}
+
+func TestStrftime(t *testing.T) {
+	for _, tt := range strftimeTests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			out := Strftime(tt.pattern, tt.t)
+			if out != tt.out {
+				t.Errorf("Strftime should have returned %q, but it returned %q", tt.out, out)
+			}
+		})
+	}
+}
+
+var strptimeTests = []struct {
+	pattern string
+	in      string
+	out     time.Time
+	success bool
+}{
+	{"%Y-%m-%d %H:%M:%S %z", "2001-03-10 17:16:18 +0000", phpTime, true},
+	{"%Y-%m-%d %H:%M:%S %z", "2004-02-12 15:19:21 +05:30", time.Date(2004, 2, 12, 9, 49, 21, 0, time.UTC), true},
+	{"%Y-%m-%dT%H:%M:%S.%f%z", "2004-02-12T15:19:21.5Z", time.Date(2004, 2, 12, 15, 19, 21, 500000000, time.UTC), true},
+	{"%c", "Sat Mar 10 17:16:18 2001", phpTime, true},
+	{"%d/%b/%Y:%H:%M:%S %z", "10/Oct/2000:13:55:36 -0700", time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC), true},
+	{"%A, %B %e, %Y %I:%M %p", "saturday, march 10, 2001 5:16 pm", time.Date(2001, 3, 10, 17, 16, 0, 0, time.UTC), true},
+	{"%D %T", "03/10/01 17:16:18", phpTime, true},
+	{"%y", "69", time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC), true},
+	{"%y", "68", time.Date(2068, 1, 1, 0, 0, 0, 0, time.UTC), true},
+	{"%Y %j", "2004 366", time.Date(2004, 12, 31, 0, 0, 0, 0, time.UTC), true},
+	{"%G-W%V-%u", "2004-W53-6", time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC), true},
+	{"%H:%M", "17:16", time.Date(1900, 1, 1, 17, 16, 0, 0, time.UTC), true},
+	{"%s", "984244578", phpTime, true},
+	{"%Y%m%d  %Z", "20010310UTC", time.Date(2001, 3, 10, 0, 0, 0, 0, time.UTC), true},
+	{"%Y-%m-%d", "2001-02-29", time.Time{}, false},
+	{"%Y-%m-%d", "2001-13-01", time.Time{}, false},
+	{"%Y-%m-%d", "2001-03-10 17:16", time.Time{}, false},
+	{"%Y %j", "2001 366", time.Time{}, false},
+	{"%H:%M", "5pm", time.Time{}, false},
+	{"%Z", "EST", time.Time{}, false},
+	{"%U", "10", time.Time{}, false},
+}
+
+func TestStrptime(t *testing.T) {
+	for _, tt := range strptimeTests {
+		t.Run(tt.pattern+" "+tt.in, func(t *testing.T) {
+			out, err := Strptime(tt.pattern, tt.in)
+			if !tt.success {
+				if err == nil {
+					t.Errorf("%q should not have been accepted, but it parsed to %v", tt.in, out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !out.Equal(tt.out) {
+				t.Errorf("Strptime should have returned %v, but it returned %v", tt.out, out)
+			}
+		})
+	}
+}
+
+func TestStrptimeKeepsOffset(t *testing.T) {
+	pattern := "%Y-%m-%d %H:%M:%S %z"
+	in := "2004-02-12 15:19:21 -0330"
+
+	out, err := Strptime(pattern, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := Strftime(pattern, out); s != in {
+		t.Errorf("Strftime should have given back %q, but it gave %q", in, s)
+	}
+}
@@ -9,44 +9,418 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Parse takes an English string - such as "next Friday 3 pm" - and an int64 unix timestamp to compare it with.
 // It returns the translated English text into an int64 unix timestamp, or an error if the input cannot be recognized.
-func Parse(s string, relativeTo int64) (int64, error) {
+// Options, such as InLocale(French), change how the string is read.
+func Parse(s string, relativeTo int64, opts ...Option) (int64, error) {
+	r, err := parse(s, opts...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	t, err := r.resolve(relativeTo)
+
+	if err != nil {
+		return 0, outOfRange(err, s)
+	}
+
+	return t.Unix(), nil
+}
+
+// outOfRange fills in s as the value of a *RangeError that doesn't say which value is out of range
+func outOfRange(err error, s string) error {
+	if e, ok := err.(*RangeError); ok && e.Value == "" {
+		return &RangeError{Value: s}
+	}
+
+	return err
+}
+
+// Option changes how Parse reads its input.
+type Option func(*options)
+
+type options struct {
+	locale    *Locale
+	weekStart time.Weekday
+	prefer    Preference
+	overflow  Overflow
+	strict    bool
+	fuzzy     bool
+	bare      bool
+	skipped   *[]string
+}
+
+// newOptions applies opts over the defaults
+func newOptions(opts []Option) *options {
+	o := &options{locale: English, weekStart: time.Monday}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// configure copies the options that decide how a result resolves into r
+func (o *options) configure(r *result) {
+	r.weekStart = o.weekStart
+	r.prefer = o.prefer
+	r.overflow = o.overflow
+}
+
+// InLocale makes Parse read the words and phrases of the given locale, such as "demain 15h" in French.
+func InLocale(l *Locale) Option {
+	return func(o *options) {
+		o.locale = l
+	}
+}
+
+// WeekStart sets the day weeks start on, which is Monday by default. It decides the days that "this week",
+// "start of week" and "sunday next week" refer to, and the weeks that recurrences such as "every other week" skip.
+func WeekStart(d time.Weekday) Option {
+	return func(o *options) {
+		o.weekStart = d
+	}
+}
+
+// check returns the error StrictCalendar asks for, if r gives a date or time that doesn't exist
+func (o *options) check(r *result) error {
+	if !o.strict {
+		return nil
+	}
+
+	return r.validate()
+}
+
+// StrictCalendar makes Parse return a *CalendarError for dates and times that don't exist, such as "February 30",
+// "2015-02-29" or "12:30:60", rather than roll them over into the next month, day or minute as PHP does. It reports
+// numeric dates and times with a field out of range, such as "2015-13-45" or "25:00", which Parse otherwise fails
+// to read or reads as something else, the same way. Days without a year only have to exist in some year, as
+// "February 29" does. Either way, "24:00" is the end of the day, which is midnight of the next one, and
+// "23:59:60 UTC" is a leap second, read as the first second of the next day, since a time.Time can't hold it.
+func StrictCalendar() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// Preference decides which occurrence a date that doesn't say, such as "Friday", "March 3", "the 15th" or
+// "5pm", refers to.
+type Preference int
+
+// Supported preferences. The zero Preference resolves dates as PHP does: "March 3" is in the current year,
+// "5pm" is today and "Friday" is the next Friday, or today on a Friday.
+const (
+	// PreferFuture picks the first occurrence that is not before the reference time
+	PreferFuture Preference = iota + 1
+	// PreferPast picks the last occurrence that is not after the reference time
+	PreferPast
+	// PreferNearest picks the occurrence closest to the reference time, the future one on a tie
+	PreferNearest
+)
+
+// Prefer makes Parse resolve dates that leave out their year, month, week or day, and say nothing relative,
+// to the occurrence the preference picks, so that "March 3" on March 10 is next year's with PreferFuture, and
+// "5pm" at 6pm is tomorrow's. A date without a time of day starts at midnight, so that PreferFuture takes
+// "Friday" on a Friday to be the next one, and PreferPast today.
+func Prefer(p Preference) Option {
+	return func(o *options) {
+		o.prefer = p
+	}
+}
+
+// Overflow decides where shifting a date by months, quarters or years lands when its day doesn't exist in
+// the month it lands in, as for "January 31 +1 month".
+type Overflow int
+
+// Supported overflows.
+const (
+	// OverflowPHP rolls the day over into the next month, as PHP does: "January 31 +1 month" is March 3rd,
+	// and "February 29 2024 +1 year" is March 1st 2025.
+	OverflowPHP Overflow = iota
+	// OverflowClamp keeps the day within the month, on its last day: "January 31 +1 month" is February
+	// 28th, and "February 29 2024 +1 year" is February 28th 2025.
+	OverflowClamp
+)
+
+// MonthOverflow sets where shifts by months, quarters and years land when the day they start from, such as
+// the 31st, is past the end of the month they land in. It is OverflowPHP by default.
+func MonthOverflow(o Overflow) Option {
+	return func(opts *options) {
+		opts.overflow = o
+	}
+}
+
+// BareTimestamps makes Parse read numbers of ten digits or more that it otherwise can't, such as "1569600000123",
+// as Unix timestamps. It takes those of up to 11 digits to count seconds, up to 14 milliseconds, up to 17
+// microseconds, and any longer nanoseconds. Timestamps that start with "@" count seconds unless they end with
+// one of the units "ms", "us" or "ns", as in "@1569600000123ms", with or without this option.
+func BareTimestamps() Option {
+	return func(o *options) {
+		o.bare = true
+	}
+}
+
+// formats returns the formats the options read strings with
+func (o *options) formats() []format {
+	switch {
+	case o.strict && o.bare:
+		return strictBareFormats
+	case o.strict:
+		return strictFormats
+	case o.bare:
+		return bareFormats
+	}
+
+	return allFormats
+}
+
+// Fuzzy makes Parse skip the words it doesn't recognize, such as "meeting" and "please" in "meeting at 3pm
+// on Friday please", rather than fail, as long as it recognizes a date or time in what is left. Parse stores
+// the words, numbers and punctuation it skipped in skipped, unless it is nil, so that callers can judge how
+// much of the string made sense.
+func Fuzzy(skipped *[]string) Option {
+	return func(o *options) {
+		o.fuzzy = true
+		o.skipped = skipped
+	}
+}
+
+// parse runs the formats over s, in order, until the whole string has been consumed.
+// It returns the accumulated result, which is still relative to no point in time.
+func parse(s string, opts ...Option) (*result, error) {
+	o := newOptions(opts)
+
+	var r *result
+	var err error
+
+	translated := o.locale.translate(s)
+
+	if o.fuzzy {
+		var skipped []string
+		r, skipped, err = parseFuzzy(translated, o.formats())
+		if o.skipped != nil {
+			*o.skipped = skipped
+		}
+	} else {
+		r, err = parseFormats(translated, o.formats())
+	}
+
+	if err != nil {
+		return nil, untranslated(err, s, translated)
+	}
+
+	if err := o.check(r); err != nil {
+		return nil, err
+	}
+
+	o.configure(r)
+
+	return r, nil
+}
+
+// allFormats are the formats parse runs, whose rules are built once, bareFormats those it runs for
+// BareTimestamps, and strictFormats and strictBareFormats those it runs for StrictCalendar
+var (
+	allFormats        = formats()
+	bareFormats       = withBareTimestamps(allFormats)
+	strictFormats     = withOutOfRange(allFormats)
+	strictBareFormats = withOutOfRange(bareFormats)
+)
+
+// parseFormats is parse restricted to the given formats.
+func parseFormats(s string, formats []format) (*result, error) {
 	r := &result{}
-	formats := formats()
+	steps, pos := matchFormats(lex(s), 0, formats)
+
+	err := apply(r, steps)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if pos < len(s) {
+		if len(steps) > 0 {
+			s = strings.TrimSpace(s[pos:])
+		}
+		return nil, &inputError{s}
+	}
+
+	return r, nil
+}
+
+// inputError is the error for input, or the part of it, that no format reads
+type inputError struct {
+	text string
+}
+
+func (e *inputError) Error() string {
+	return fmt.Sprintf(`strtotime: Unrecognizable input: "%v"`, e.text)
+}
+
+// untranslated quotes s, as it was given, in place of its translation in an inputError, since the
+// translation is not something the caller wrote
+func untranslated(err error, s, translated string) error {
+	if _, ok := err.(*inputError); ok && s != translated {
+		return &inputError{s}
+	}
+
+	return err
+}
+
+// parseFuzzy is parseFormats skipping the tokens no format matches, which it returns. There must be
+// something other than filler left.
+func parseFuzzy(s string, formats []format) (*result, []string, error) {
+	in := lex(s)
+
+	var steps []step
+	var skipped []string
+
+	for pos := 0; ; {
+		matched, end := matchFormats(in, pos, formats)
+		steps = append(steps, matched...)
+
+		t, _ := in.at(end)
+
+		if t == nil {
+			break
+		}
+
+		if t.kind != tokenSpace {
+			skipped = append(skipped, t.text)
+		}
+
+		pos = skipSpace(s, t.pos+len(t.text))
+	}
+
+	found := false
+
+	for _, step := range steps {
+		found = found || !isFiller(step)
+	}
+
+	if !found {
+		return nil, skipped, &inputError{s}
+	}
+
+	r := &result{}
+	err := apply(r, steps)
+
+	if err != nil {
+		return nil, skipped, err
+	}
+
+	return r, skipped, nil
+}
+
+// step is a format matched from start to end, and the inputs of its callback
+type step struct {
+	format     format
+	start, end int
+	inputs     []string
+}
+
+// matchFormats matches formats from pos on, for as long as one of them matches: at each position, it runs
+// the formats, in order, whose rule can start with the token there, and skips the white space after the first
+// one that matches. It returns the formats it matched and the position it got to, leaving any filler
+// at the end unmatched.
+func matchFormats(in *input, pos int, formats []format) ([]step, int) {
+	var steps []step
+
 	for {
-		noMatch := true
+		t, _ := in.at(pos)
+		matched := false
+
 		for _, format := range formats {
+			if t == nil || format.rule.starts&t.kind.set() == 0 {
+				continue
+			}
 
-			re := regexp.MustCompile(format.regex)
-			match := re.FindStringSubmatch(s)
+			match := format.rule.parse(in, pos)
 
 			if len(match) <= 0 {
 				continue
 			}
 
-			noMatch = false
+			steps = append(steps, step{format, pos, pos + len(match[0]), match[1:]})
+			pos = skipSpace(in.s, pos+len(match[0]))
+			matched = true
+			break
+		}
 
-			err := format.callback(r, match[1:]...)
+		if !matched {
+			return dropTrailingFiller(steps, pos)
+		}
+	}
+}
 
-			if err != nil {
-				return 0, err
-			}
+// dropTrailingFiller unmatches the filler words, such as "at", that nothing comes after, along with the white
+// space around them, as they only join dates and times
+func dropTrailingFiller(steps []step, pos int) ([]step, int) {
+	n := len(steps)
 
-			s = strings.TrimSpace(re.ReplaceAllString(s, ""))
-			break
-		}
+	for n > 0 && isFiller(steps[n-1]) {
+		n--
+	}
 
-		if len(s) == 0 {
-			return r.toDate(relativeTo).Unix(), nil
+	for _, step := range steps[n:] {
+		if step.format.name == "filler" {
+			return steps[:n], steps[n].start
 		}
+	}
 
-		if noMatch {
-			return 0, fmt.Errorf(`strtotime: Unrecognizable input: "%v"`, s)
+	return steps, pos
+}
+
+// apply runs the callbacks of the steps over r, in order
+func apply(r *result, steps []step) error {
+	for _, step := range steps {
+		err := step.format.callback(r, step.inputs...)
+
+		if err != nil {
+			return err
 		}
 	}
+
+	return nil
+}
+
+// skipSpace returns the position of the first character from pos on that isn't white space
+func skipSpace(s string, pos int) int {
+	return len(s) - len(strings.TrimLeftFunc(s[pos:], unicode.IsSpace))
+}
+
+// tokenBoundary reports whether s can be split into tokens at byte i
+func tokenBoundary(s string, i int) bool {
+	if i <= 0 || i >= len(s) {
+		return true
+	}
+
+	before, _ := utf8.DecodeLastRuneInString(s[:i])
+	after, _ := utf8.DecodeRuneInString(s[i:])
+
+	return !(unicode.IsLetter(before) && unicode.IsLetter(after)) && !(unicode.IsDigit(before) && unicode.IsDigit(after))
+}
+
+// processFraction converts the digits after the point of a second, such as "040" in "01:59:59.040", to
+// nanoseconds, dropping those past the ninth
+func processFraction(digits string) (int, error) {
+	if len(digits) > 9 {
+		digits = digits[:9]
+	}
+
+	n, err := strconv.Atoi(digits)
+
+	for i := len(digits); i < 9; i++ {
+		n *= 10
+	}
+
+	return n, err
 }
 
 //processMeridian converts 12 hour format type to 24 hour format
@@ -137,22 +511,10 @@ func lookupMonth(m string) int {
 	return monthMap[strings.ToLower(m)]
 }
 
+// lookupNumberToMonth turns a 0-based month into a time.Month. Months outside 0-11, left over by
+// relative shifts such as "+8 months", are left for time.Date to carry over into the year.
 func lookupNumberToMonth(m int) time.Month {
-	monthMap := map[int]time.Month{
-		0:  time.January,
-		1:  time.February,
-		2:  time.March,
-		3:  time.April,
-		4:  time.May,
-		5:  time.June,
-		6:  time.July,
-		7:  time.August,
-		8:  time.September,
-		9:  time.October,
-		10: time.November,
-		11: time.December,
-	}
-	return monthMap[m]
+	return time.Month(m + 1)
 }
 
 func lookupWeekday(day string, desiredSundayNumber int) int {
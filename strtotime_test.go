@@ -1,6 +1,7 @@
 package strtotime
 
 import (
+	"reflect"
 	"testing"
 	"time"
 )
@@ -35,6 +36,9 @@ var parseTests = []struct {
 	{"2008-10-31T15:07:38.034567890GMT-05:00", 1225483658, true},
 	{"2008-10-31T15:07:38.034567890Z", 1225465658, true},
 	{"2008-10-31T15:07:38", 1225465658, true},
+	{"2008-10-31T15:07:38Z", 1225465658, true},
+	{"2008-10-31T15:07", 1225465620, true},
+	{"20081031T150738Z", 1225465658, true},
 	{"2008:10:31 15:07:38", 1225465658, true},
 	{"20081031T15:07:38", 1225465658, true},
 	{"20081031T150738", 1225465658, true},
@@ -87,11 +91,35 @@ var parseTests = []struct {
 	{"3 days ago", 1435842000, true},
 	{"-1 day +1 month", 1438693200, true},
 	{"-1 day 0 month", 1436014800, true},
+	// "h" and "hr" are hours in ParseDuration only, since "15h" is a time of day in other languages
+	{"+2h", 0, false},
+	{"tomorrow 15h", 0, false},
 	{"1359", time.Date(now.Year(), now.Month(), now.Day(), 13, 59, 0, 0, time.UTC).Unix(), true},
 	{"1993", 741877200, true},
 	{" ", 1436101200, true},
+	{"yesterday at 4:05 PM", time.Date(now.Year(), now.Month(), now.Day()-1, 16, 5, 0, 0, time.UTC).Unix(), true},
+	{"in 2 hours", time.Date(now.Year(), now.Month(), now.Day(), now.Hour()+2, now.Minute(), now.Second(), now.Nanosecond(), time.UTC).Unix(), true},
+	{"last March", time.Date(now.Year(), time.March, now.Day(), 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"next March", time.Date(now.Year()+1, time.March, now.Day(), 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"next December", time.Date(now.Year(), time.December, now.Day(), 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"last July", time.Date(now.Year()-1, time.July, now.Day(), 0, 0, 0, 0, time.UTC).Unix(), true},
 	{"November 15 2019 front of 7pm", 1573843500, true},
 	{"January 00 2019 front of 24am", 1546299900, true},
+	{"Tomorrow", time.Date(now.Year(), now.Month(), now.Day()+1, now.Hour(), 0, 0, 0, time.UTC).Unix(), true},
+	{"YESTERDAY NOON", time.Date(now.Year(), now.Month(), now.Day()-1, 12, 0, 0, 0, time.UTC).Unix(), true},
+	{"Now", now.Unix(), true},
+	{"Today", time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"First day of next month", time.Date(now.Year(), now.Month()+1, 1, now.Hour(), 0, 0, 0, time.UTC).Unix(), true},
+	{"2008-10-31T15:07:38z", time.Date(2008, 10, 31, 15, 7, 38, 0, time.UTC).Unix(), true},
+	{"2008-w44-5", time.Date(2008, 10, 31, 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"2008-W28", time.Date(2008, 7, 7, 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"2008W27", time.Date(2008, 6, 30, 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"2015W53", time.Date(2015, 12, 28, 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"the 15th", time.Date(now.Year(), now.Month(), 15, 0, 0, 0, 0, time.UTC).Unix(), true},
+	{"31st 3pm", time.Date(now.Year(), now.Month(), 31, 15, 0, 0, 0, time.UTC).Unix(), true},
+	{"3pm friday", time.Date(2015, 7, 10, 15, 0, 0, 0, time.UTC).Unix(), true},
+	{"3pm next friday", time.Date(2015, 7, 10, 15, 0, 0, 0, time.UTC).Unix(), true},
+	{"friday", time.Date(2015, 7, 10, 0, 0, 0, 0, time.UTC).Unix(), true},
 
 	// {"first monday of december", 1436101200, true},
 }
@@ -110,6 +138,201 @@ func TestParse(t *testing.T) {
 	}
 }
 
+var wednesday = time.Date(2015, 7, 8, 13, 0, 0, 0, time.UTC)
+
+var weekStartTests = []struct {
+	in        string
+	ref       time.Time
+	weekStart time.Weekday
+	out       time.Time
+}{
+	{"this week", now, time.Monday, time.Date(2015, 6, 29, 13, 0, 0, 0, time.UTC)},
+	{"this week", now, time.Sunday, time.Date(2015, 7, 5, 13, 0, 0, 0, time.UTC)},
+	{"start of week", now, time.Monday, time.Date(2015, 6, 29, 0, 0, 0, 0, time.UTC)},
+	{"start of week", now, time.Sunday, time.Date(2015, 7, 5, 0, 0, 0, 0, time.UTC)},
+	{"end of the week", now, time.Monday, time.Date(2015, 7, 5, 23, 59, 59, 0, time.UTC)},
+	{"end of the week", now, time.Sunday, time.Date(2015, 7, 11, 23, 59, 59, 0, time.UTC)},
+	{"monday this week", now, time.Monday, time.Date(2015, 6, 29, 0, 0, 0, 0, time.UTC)},
+	{"monday this week", now, time.Sunday, time.Date(2015, 7, 6, 0, 0, 0, 0, time.UTC)},
+	{"sunday next week", wednesday, time.Monday, time.Date(2015, 7, 19, 0, 0, 0, 0, time.UTC)},
+	{"sunday next week", wednesday, time.Sunday, time.Date(2015, 7, 12, 0, 0, 0, 0, time.UTC)},
+	{"start of next week", wednesday, time.Monday, time.Date(2015, 7, 13, 0, 0, 0, 0, time.UTC)},
+	{"start of next week", wednesday, time.Sunday, time.Date(2015, 7, 12, 0, 0, 0, 0, time.UTC)},
+	{"beginning of next week 9am", wednesday, time.Saturday, time.Date(2015, 7, 11, 9, 0, 0, 0, time.UTC)},
+	{"end of last week", wednesday, time.Monday, time.Date(2015, 7, 5, 23, 59, 59, 0, time.UTC)},
+	{"end of last week", wednesday, time.Sunday, time.Date(2015, 7, 4, 23, 59, 59, 0, time.UTC)},
+}
+
+func TestWeekStart(t *testing.T) {
+	for _, tt := range weekStartTests {
+		t.Run(tt.in+" from "+tt.weekStart.String(), func(t *testing.T) {
+			u, err := Parse(tt.in, tt.ref.Unix(), WeekStart(tt.weekStart))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u != tt.out.Unix() {
+				t.Errorf("Result should have been %v, but it was %v", tt.out, time.Unix(u, 0).UTC())
+			}
+		})
+	}
+
+	rec, err := ParseRecurrence("every other week", wednesday, WeekStart(time.Sunday))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.WeekStart != time.Sunday {
+		t.Errorf("Recurrence weeks should have started on Sunday, but they started on %v", rec.WeekStart)
+	}
+}
+
+var preferTests = []struct {
+	in     string
+	prefer Preference
+	ref    time.Time
+	out    time.Time
+}{
+	{"March 3", PreferFuture, now, time.Date(2016, 3, 3, 0, 0, 0, 0, time.UTC)},
+	{"March 3", PreferPast, now, time.Date(2015, 3, 3, 0, 0, 0, 0, time.UTC)},
+	{"March 3", PreferNearest, now, time.Date(2015, 3, 3, 0, 0, 0, 0, time.UTC)},
+	{"December 3", PreferNearest, now, time.Date(2015, 12, 3, 0, 0, 0, 0, time.UTC)},
+	{"the 15th", PreferFuture, now, time.Date(2015, 7, 15, 0, 0, 0, 0, time.UTC)},
+	{"the 15th", PreferPast, now, time.Date(2015, 6, 15, 0, 0, 0, 0, time.UTC)},
+	{"31st", PreferPast, now, time.Date(2015, 5, 31, 0, 0, 0, 0, time.UTC)},
+	{"February 29", PreferFuture, now, time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC)},
+	{"February 29", PreferPast, now, time.Date(2012, 2, 29, 0, 0, 0, 0, time.UTC)},
+	{"noon", PreferFuture, now, time.Date(2015, 7, 6, 12, 0, 0, 0, time.UTC)},
+	{"noon", PreferPast, now, time.Date(2015, 7, 5, 12, 0, 0, 0, time.UTC)},
+	{"11pm", PreferPast, now, time.Date(2015, 7, 4, 23, 0, 0, 0, time.UTC)},
+	{"11pm", PreferNearest, now, time.Date(2015, 7, 5, 23, 0, 0, 0, time.UTC)},
+	{"1pm", PreferFuture, now, time.Date(2015, 7, 5, 13, 0, 0, 0, time.UTC)},
+	{"sunday", PreferFuture, now, time.Date(2015, 7, 12, 0, 0, 0, 0, time.UTC)},
+	{"sunday", PreferPast, now, time.Date(2015, 7, 5, 0, 0, 0, 0, time.UTC)},
+	{"sunday 2pm", PreferFuture, now, time.Date(2015, 7, 5, 14, 0, 0, 0, time.UTC)},
+	{"friday", PreferPast, now, time.Date(2015, 7, 3, 0, 0, 0, 0, time.UTC)},
+	{"friday", PreferNearest, now, time.Date(2015, 7, 3, 0, 0, 0, 0, time.UTC)},
+	{"friday", PreferNearest, wednesday, time.Date(2015, 7, 10, 0, 0, 0, 0, time.UTC)},
+
+	// dates that say which one they mean keep it
+	{"next friday", PreferPast, now, time.Date(2015, 7, 10, 0, 0, 0, 0, time.UTC)},
+	{"March 3 2015", PreferFuture, now, time.Date(2015, 3, 3, 0, 0, 0, 0, time.UTC)},
+	{"next March", PreferPast, now, time.Date(2016, 3, 5, 0, 0, 0, 0, time.UTC)},
+	{"yesterday noon", PreferFuture, now, time.Date(2015, 7, 4, 12, 0, 0, 0, time.UTC)},
+	{"now", PreferFuture, now, now},
+}
+
+func TestPrefer(t *testing.T) {
+	for _, tt := range preferTests {
+		t.Run(tt.in, func(t *testing.T) {
+			u, err := Parse(tt.in, tt.ref.Unix(), Prefer(tt.prefer))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u != tt.out.Unix() {
+				t.Errorf("Result should have been %v, but it was %v", tt.out, time.Unix(u, 0).UTC())
+			}
+		})
+	}
+}
+
+var overflowTests = []struct {
+	in    string
+	php   time.Time
+	clamp time.Time
+}{
+	{"January 31 +1 month", time.Date(2015, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2015, 2, 28, 0, 0, 0, 0, time.UTC)},
+	{"2016-01-31 next month", time.Date(2016, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC)},
+	{"2015-03-31 last month", time.Date(2015, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2015, 2, 28, 0, 0, 0, 0, time.UTC)},
+	{"February 29 2024 +1 year", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+	{"2024-02-29 last year", time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC)},
+	{"2015-11-30 +1 quarter", time.Date(2016, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC)},
+	{"2015-05-31 -1 quarter", time.Date(2015, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2015, 2, 28, 0, 0, 0, 0, time.UTC)},
+	{"2015-01-31 +1 month +1 day", time.Date(2015, 3, 4, 0, 0, 0, 0, time.UTC), time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)},
+	{"2015-01-15 +1 month", time.Date(2015, 2, 15, 0, 0, 0, 0, time.UTC), time.Date(2015, 2, 15, 0, 0, 0, 0, time.UTC)},
+	{"last day of next month", time.Date(2015, 8, 31, 13, 0, 0, 0, time.UTC), time.Date(2015, 8, 31, 13, 0, 0, 0, time.UTC)},
+}
+
+func TestMonthOverflow(t *testing.T) {
+	for _, tt := range overflowTests {
+		t.Run(tt.in, func(t *testing.T) {
+			for overflow, want := range map[Overflow]time.Time{OverflowPHP: tt.php, OverflowClamp: tt.clamp} {
+				u, err := Parse(tt.in, now.Unix(), MonthOverflow(overflow))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if u != want.Unix() {
+					t.Errorf("Result with overflow %v should have been %v, but it was %v", overflow, want, time.Unix(u, 0).UTC())
+				}
+			}
+		})
+	}
+}
+
+var tokenBoundaryTests = []string{
+	"nowhere",
+	"snooze",
+	"tomorrowland",
+	"todays",
+	"noonish",
+	"yesterdays news",
+	"20081",
+}
+
+func TestTokenBoundaries(t *testing.T) {
+	for _, in := range tokenBoundaryTests {
+		t.Run(in, func(t *testing.T) {
+			if u, err := Parse(in, now.Unix()); err == nil {
+				t.Errorf("%q should not have been accepted, but it parsed to %v", in, time.Unix(u, 0).UTC())
+			}
+		})
+	}
+}
+
+func TestLoneFiller(t *testing.T) {
+	for _, in := range []string{"at", "in", "on", "tomorrow at", "at, on"} {
+		t.Run(in, func(t *testing.T) {
+			if u, err := Parse(in, now.Unix()); err == nil {
+				t.Errorf("%q should not have been accepted, but it parsed to %v", in, time.Unix(u, 0).UTC())
+			}
+		})
+	}
+}
+
+var fuzzyTests = []struct {
+	in      string
+	out     time.Time
+	skipped []string
+}{
+	{"meeting at 3pm on Friday please", time.Date(2015, 7, 10, 15, 0, 0, 0, time.UTC), []string{"meeting", "please"}},
+	{"meeting on Friday at 3pm please", time.Date(2015, 7, 10, 15, 0, 0, 0, time.UTC), []string{"meeting", "please"}},
+	{"Due: 2024-05-01 (tentative)", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), []string{"Due", ":", "(", "tentative", ")"}},
+	{"tomorrow", time.Date(2015, 7, 6, 13, 0, 0, 0, time.UTC), nil},
+	{"call me back tomorrow at noon, thanks", time.Date(2015, 7, 6, 12, 0, 0, 0, time.UTC), []string{"call", "me", "back", "thanks"}},
+}
+
+func TestFuzzy(t *testing.T) {
+	for _, tt := range fuzzyTests {
+		t.Run(tt.in, func(t *testing.T) {
+			var skipped []string
+			u, err := Parse(tt.in, now.Unix(), Fuzzy(&skipped))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := time.Unix(u, 0).UTC(); !got.Equal(tt.out) {
+				t.Errorf("Result should have been %v, but it was %v", tt.out, got)
+			}
+			if !reflect.DeepEqual(skipped, tt.skipped) {
+				t.Errorf("Skipped should have been %q, but it was %q", tt.skipped, skipped)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "nothing to see here", "at, on"} {
+		if _, err := Parse(in, now.Unix(), Fuzzy(nil)); err == nil {
+			t.Errorf("%q should not have been accepted", in)
+		}
+	}
+}
+
 func TestProcessMeridian(t *testing.T) {
 	h := processMeridian(12, "am")
 	if h != 0 {